@@ -1,11 +1,12 @@
 package goui
 
 import (
+	"context"
 	"fmt"
 	"iter"
+	"runtime"
+	"sync"
 
-	"github.com/mkch/gg"
-	"github.com/mkch/gg/errortrace"
 	"github.com/mkch/goui/internal/tricks"
 	"github.com/mkch/goui/native"
 )
@@ -13,12 +14,19 @@ import (
 type Context struct {
 	app    *App    // can't be nil
 	window *window // can't be nil
+	// element is the stateless or stateful element being built with this context.
+	// Nil for the contexts not associated with an element.
+	element buildableElement
+	// parent is the context the element is built with, which is
+	// the context of the nearest stateless or stateful ancestor, if any.
+	parent *Context
 }
 
 // newMockContext creates and returns a new mock goui.Context for testing.
+// No native backend is created unless config.Backend is set.
 func newMockContext(config *AppConfig) *Context {
 	return &Context{
-		app:    NewApp(config),
+		app:    newApp(config, config.Backend),
 		window: &window{},
 	}
 }
@@ -28,10 +36,28 @@ func (ctx *Context) NativeWindow() native.Handle {
 	return ctx.window.Handle
 }
 
+// NativeBackend returns the native backend of the app.
+func (ctx *Context) NativeBackend() native.Backend {
+	return ctx.app.backend
+}
+
+// Context returns the context.Context of the stateless or stateful element this context
+// is associated with. For native elements, it is the context of the nearest stateless or
+// stateful ancestor. The returned context is cancelled when the element is destroyed by
+// reconciliation, or when the window is closed, so it can be used to stop the goroutines
+// and requests started by the element. For the contexts not associated with an element,
+// the context of the window is returned.
+func (ctx *Context) Context() context.Context {
+	if ctx.element == nil {
+		return ctx.window.context()
+	}
+	return ctx.element.component().context()
+}
+
 // MessageBox shows a message box with the given title, message and icon
 // associated with this context's window.
 func (ctx *Context) MessageBox(title, message string, icon MessageBoxIcon) {
-	native.MessageBox(ctx.NativeWindow(), title, message, native.MessageBoxIcon(icon))
+	ctx.app.backend.MessageBox(ctx.NativeWindow(), title, message, native.MessageBoxIcon(icon))
 }
 
 type Widget interface {
@@ -47,15 +73,41 @@ type Container interface {
 	Exclusive(Container)
 }
 
+// SingleChildContainer is a [Container] with at most one child, which is the only one
+// laid out by its layouter. The child can't be a [Fragment], or a [StatelessWidget] or
+// [StatefulWidget] building one, where [ErrFragmentNotInContainer] is returned.
+type SingleChildContainer interface {
+	Container
+	// SingleChild is a marker method of the containers with at most one child.
+	SingleChild()
+}
+
 type App struct {
 	debug   *tricks.Debug
-	app     native.App
+	backend native.Backend
 	windows map[ID]*window
+	// recordLayout specifies whether the layout results of all layouters are recorded,
+	// even if layout outline is not enabled. See [debugLayouter].
+	recordLayout bool
+	frame        frame
+	// guiGoroutine is the ID of the goroutine running the app,
+	// that is the goroutine calling NewApp or Run.
+	guiGoroutine uint64
+	onError      func(*Context, error)
+	failure      failure // The widgets the last build or reconcile error is returned through.
+	onPatch      func(*Context, Element, []Patch)
+	// recreated records the consecutive recreations of children in debug mode,
+	// see [App.checkRecreated].
+	recreated map[Element]map[int]int
+	globals   map[*GlobalID]globalElement // The registered elements of GlobalIDs.
+	inactive  inactiveElements
+	// layoutCacheStats counts the layout cache hits in debug mode. Nil if debug mode is off.
+	layoutCacheStats *LayoutCacheStats
 }
 
 // Post posts a function to be executed on the main GUI goroutine.
 func (app *App) Post(f func()) error {
-	return app.app.Post(f)
+	return app.backend.Post(f)
 }
 
 // AppConfig is the configuration for creating a new App.
@@ -63,7 +115,25 @@ type AppConfig struct {
 	// Debug is the debug configuration for the app.
 	// If Debug is non-nil, debug mode is on, and the fields in Debug control which features are enabled.
 	// If Debug is nil, debug mode is off.
+	// In debug mode, children of a container with the same ID are reported as a
+	// [DuplicateIDError], and a warning is logged for the elements recreated on
+	// every rebuild, which usually have unstable IDs.
 	Debug *Debug
+	// Backend is the native GUI implementation used by the app.
+	// If Backend is nil, the default backend of the platform is used,
+	// see [native.NewDefaultBackend]. On the platforms without a default backend,
+	// such as the ones other than Windows, Backend must be set, or [NewApp] panics.
+	Backend native.Backend
+	// OnError is called on the GUI goroutine with every error occurred in the framework,
+	// such as an error returned while building or laying out, which is an [*Error]
+	// describing the phase and the widget path.
+	// The app keeps running after OnError returns.
+	// If OnError is nil, the error is logged with [slog].
+	OnError func(ctx *Context, err error)
+	// OnPatch, if non-nil, is called with the operations performed on the children
+	// of parent every time they are reconciled, which is useful for tests and debug logging.
+	// See [Patch] and [ChildPatcher].
+	OnPatch func(ctx *Context, parent Element, patches []Patch)
 }
 
 // Debug is the debug configuration for the app.
@@ -74,36 +144,85 @@ type Debug struct {
 
 // NewApp creates and returns a new App instance.
 // The app is setup with the given config. If config is nil, default configuration is used.
+// NewApp panics if no backend is configured and the platform has no default backend,
+// which is a programming error, like building for an unsupported platform.
 func NewApp(config *AppConfig) *App {
-	return &App{
-		debug:   gg.If(config == nil, nil, (*tricks.Debug)(config.Debug).Clone()),
-		app:     native.NewApp(),
-		windows: make(map[ID]*window),
+	if config == nil {
+		config = &AppConfig{}
+	}
+	backend := config.Backend
+	if backend == nil {
+		backend = native.NewDefaultBackend()
 	}
+	if backend == nil {
+		panic(fmt.Sprintf("no default native backend on %v, AppConfig.Backend must be set", runtime.GOOS))
+	}
+	return newApp(config, backend)
+}
+
+// newApp creates a new App with the given config and backend.
+// Config can't be nil.
+func newApp(config *AppConfig, backend native.Backend) *App {
+	onError := config.OnError
+	if onError == nil {
+		onError = defaultOnError
+	}
+	app := &App{
+		debug:        (*tricks.Debug)(config.Debug).Clone(),
+		backend:      backend,
+		windows:      make(map[ID]*window),
+		guiGoroutine: goroutineID(),
+		onError:      onError,
+		onPatch:      config.OnPatch,
+	}
+	if app.debug != nil {
+		app.layoutCacheStats = &LayoutCacheStats{}
+	}
+	return app
+}
+
+// LayoutCacheStats returns the counters of the layout cache since the app is created.
+// The counters are recorded in debug mode only, and are zero otherwise.
+func (app *App) LayoutCacheStats() LayoutCacheStats {
+	if app.layoutCacheStats == nil {
+		return LayoutCacheStats{}
+	}
+	return *app.layoutCacheStats
 }
 
 func (app *App) Run() int {
-	ctx := &Context{app: app}
+	app.guiGoroutine = goroutineID()
+	app.buildWindows()
+	return app.backend.Run()
+}
+
+// buildWindows builds and lays out the element trees of all windows.
+// The errors are passed to [AppConfig.OnError], and the first one is returned.
+// A window failing to build is left empty.
+func (app *App) buildWindows() (firstErr error) {
 	for _, window := range app.windows {
-		if window.Window.Root != nil {
-			ctx.window = window
-			elem, layouter, err := buildElementTree(ctx, window.Window.Root)
-			if err != nil {
-				errortrace.Panic(err)
-			}
-			window.Root = elem
-			window.Layouter = layouter
-			if err := layoutWindow(&Context{app, window}); err != nil {
-				errortrace.Panic(err)
+		if window.Window.Root == nil {
+			continue
+		}
+		ctx := &Context{app: app, window: window}
+		var err error
+		if window.Root, window.Layouter, err = buildElementTree(ctx, window.Window.Root); err != nil {
+			err = app.newError(PhaseBuild, nil, err)
+		} else if err = layoutWindow(ctx); err != nil {
+			err = app.newError(PhaseLayout, window.Root, err)
+		}
+		if err != nil {
+			app.handleError(ctx, err)
+			if firstErr == nil {
+				firstErr = err
 			}
 		}
 	}
-
-	return app.app.Run()
+	return
 }
 
 func (app *App) Exit(exitCode int) {
-	app.app.Quit(exitCode)
+	app.backend.Quit(exitCode)
 }
 
 type MessageBoxIcon native.MessageBoxIcon
@@ -116,12 +235,28 @@ const (
 )
 
 // MessageBox shows a message box with the given title, message and icon.
+func (app *App) MessageBox(title, message string, icon MessageBoxIcon) {
+	app.backend.MessageBox(nil, title, message, native.MessageBoxIcon(icon))
+}
+
+// defaultBackend returns the default backend of the platform, which is created on first use,
+// or nil if the platform has no default backend.
+var defaultBackend = sync.OnceValue(native.NewDefaultBackend)
+
+// MessageBox shows a message box with the given title, message and icon
+// with the default backend of the platform, see [native.NewDefaultBackend].
+// MessageBox panics if the platform has no default backend.
+// Use [App.MessageBox] to show it with the backend of an app.
 func MessageBox(title, message string, icon MessageBoxIcon) {
-	native.MessageBox(nil, title, message, native.MessageBoxIcon(icon))
+	backend := defaultBackend()
+	if backend == nil {
+		panic(fmt.Sprintf("no default native backend on %v", runtime.GOOS))
+	}
+	backend.MessageBox(nil, title, message, native.MessageBoxIcon(icon))
 }
 
 func layoutWindow(ctx *Context) error {
-	_, _, width, height, err := native.WindowClientRect(ctx.window.Handle)
+	_, _, width, height, err := ctx.app.backend.WindowClientRect(ctx.window.Handle)
 	if err != nil {
 		return err
 	}
@@ -135,6 +270,9 @@ func performLayoutWindow(ctx *Context, width, height int) (err error) {
 	if ctx.window.Layouter == nil {
 		return nil
 	}
+	if err = ctx.app.checkGoroutine("layout"); err != nil {
+		return
+	}
 	_, err = ctx.window.Layouter.Layout(ctx, Constraints{
 		MinWidth:  0,
 		MinHeight: 0,
@@ -155,7 +293,7 @@ func (app *App) CreateWindow(config Window) error {
 	if app.windows[config.ID] != nil {
 		return fmt.Errorf("window with ID %v already exists", config.ID)
 	}
-	handle, err := native.CreateWindow(config.Title, config.Width, config.Height)
+	handle, err := app.backend.CreateWindow(config.Title, config.Width, config.Height)
 	if err != nil {
 		return err
 	}
@@ -164,14 +302,20 @@ func (app *App) CreateWindow(config Window) error {
 		ID:     config.ID,
 		Handle: handle,
 	}
-	native.SetWindowOnSizeChangedListener(handle, func(width, height int) {
-		if err := performLayoutWindow(&Context{app, window}, width, height); err != nil {
-			panic(err)
+	app.backend.SetWindowOnSizeChangedListener(handle, func(width, height int) {
+		ctx := &Context{app: app, window: window}
+		if err := performLayoutWindow(ctx, width, height); err != nil {
+			app.handleError(ctx, app.newError(PhaseLayout, window.Root, err))
+		}
+	})
+	app.backend.SetWindowOnCloseListener(handle, func() {
+		app.closeWindow(window)
+		if config.OnClose != nil {
+			config.OnClose()
 		}
 	})
-	native.SetWindowOnCloseListener(handle, config.OnClose)
 	if app.debug.LayoutOutlineEnabled() {
-		native.EnableDrawDebugRect(handle, func() iter.Seq[native.DebugRect] {
+		app.backend.EnableDrawDebugRect(handle, func() iter.Seq[native.DebugRect] {
 			if window.Layouter == nil {
 				return func(yield func(native.DebugRect) bool) {}
 			}
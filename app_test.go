@@ -0,0 +1,96 @@
+package goui_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/native/headless"
+	"github.com/mkch/goui/widgets"
+)
+
+func TestApp_RunHeadless(t *testing.T) {
+	backend := headless.New()
+	app := goui.NewApp(&goui.AppConfig{Backend: backend})
+
+	counter := goui.StatefulWidgetFunc(func(ctx *goui.Context, updateState goui.UpdateStateFunc) *goui.WidgetState {
+		var n int
+		return &goui.WidgetState{
+			Build: func() goui.Widget {
+				return &widgets.Column{Widgets: []goui.Widget{
+					&widgets.Label{Text: fmt.Sprintf("Count: %d", n)},
+					&widgets.Button{
+						Label: "Add",
+						OnClick: func(*goui.Context) {
+							if err := updateState(func() { n++ }); err != nil {
+								t.Errorf("updateState error: %v", err)
+							}
+						},
+					},
+				}}
+			},
+		}
+	})
+	if err := app.CreateWindow(goui.Window{Width: 300, Height: 200, Root: counter}); err != nil {
+		t.Fatalf("CreateWindow error: %v", err)
+	}
+
+	app.Post(func() {
+		defer app.Exit(3)
+		win := backend.Windows()[0]
+		if len(win.Children) != 2 {
+			t.Fatalf("expected 2 native controls, got %d", len(win.Children))
+		}
+		label, button := win.Children[0], win.Children[1]
+		if label.Kind != headless.Label || button.Kind != headless.Button {
+			t.Fatalf("unexpected native controls: %v %v", label, button)
+		}
+		if label.Text != "Count: 0" {
+			t.Fatalf("unexpected label text %q", label.Text)
+		}
+		if label.Y != 0 || button.Y != label.Height {
+			t.Fatalf("unexpected positions: label.Y=%d label.Height=%d button.Y=%d", label.Y, label.Height, button.Y)
+		}
+
+		backend.ResetOps()
+		button.Click()
+		button.Click()
+		if label.Text != "Count: 0" {
+			t.Fatalf("label is updated before the frame")
+		}
+		if err := app.Flush(); err != nil {
+			t.Fatalf("Flush error: %v", err)
+		}
+		if label.Text != "Count: 2" {
+			t.Fatalf("unexpected label text %q after clicking", label.Text)
+		}
+		if len(win.Children) != 2 || win.Children[0] != label || win.Children[1] != button {
+			t.Fatalf("native controls are recreated")
+		}
+		var setText int
+		for _, op := range backend.Ops() {
+			if op.Name == "SetLabelText" {
+				setText++
+			}
+		}
+		if setText != 1 {
+			t.Fatalf("expected updates to be coalesced into 1 SetLabelText, got %d", setText)
+		}
+
+		backend.ResetOps()
+		win.Resize(400, 300)
+		var positioned int
+		for _, op := range backend.Ops() {
+			if op.Name == "SetWidgetDimensions" {
+				positioned++
+			}
+		}
+		if positioned != 2 {
+			t.Fatalf("expected 2 controls to be positioned after resizing, got %d", positioned)
+		}
+	})
+
+	if code := app.Run(); code != 3 {
+		t.Fatalf("exit code = %d, want 3", code)
+	}
+}
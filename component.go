@@ -0,0 +1,247 @@
+package goui
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"github.com/mkch/goui/signal"
+)
+
+// componentElement is the building block of the elements of [StatelessWidget] and
+// [StatefulWidget], which have exactly one child built by the widget or its state,
+// or the flattened widgets of the built [Fragment] as children.
+type componentElement struct {
+	ElementBase
+	ctx *Context // The context the element is built with. ctx.element is the element itself.
+	// fragment is true if the last built widget is a Fragment.
+	fragment bool
+	// dirty is true if the element is waiting to be rebuilt.
+	dirty     bool
+	destroyed bool
+	// inactive is true if the element is removed from the tree, but not destroyed yet.
+	// See [GlobalID].
+	inactive bool
+	// dependenciesChanged is true if the value of any dependency has changed
+	// since the last build.
+	dependenciesChanged bool
+	// dependencies are the inherited elements this element depends on, see [DependOn].
+	dependencies []*inheritedElement
+	// signals tracks the signals read in the last build.
+	signals *signal.Tracker
+	// stdCtx is the context.Context of the element, created on demand, see [Context.Context].
+	stdCtx context.Context
+	cancel context.CancelFunc
+}
+
+// context returns the context.Context of the element, which is cancelled when e is destroyed.
+func (e *componentElement) context() context.Context {
+	if e.stdCtx == nil {
+		e.stdCtx, e.cancel = context.WithCancel(e.ctx.window.context())
+		if e.destroyed {
+			e.cancel()
+		}
+	}
+	return e.stdCtx
+}
+
+func (e *componentElement) component() *componentElement {
+	return e
+}
+
+func (e *componentElement) didBuild() { /*Nop*/ }
+
+func (e *componentElement) destroy() {
+	if e.destroyed {
+		return
+	}
+	e.destroyed = true
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.clearDependencies()
+	if e.signals != nil { // Nil if the element is destroyed before being built.
+		e.signals.Stop()
+	}
+	e.ElementBase.destroy()
+}
+
+// clearDependencies unregisters e from the inherited elements it depends on.
+func (e *componentElement) clearDependencies() {
+	for _, inherited := range e.dependencies {
+		inherited.removeDependent(e)
+	}
+	e.dependencies = nil
+}
+
+// buildableElement is an element that builds its child.
+// It is implemented by the types embedding [componentElement].
+type buildableElement interface {
+	Element
+	component() *componentElement
+	// build builds the child widget.
+	build() Widget
+	// didBuild is called after the child is built or reconciled.
+	didBuild()
+}
+
+// initComponentElement sets the context of elem, which is derived from ctx.
+func initComponentElement(ctx *Context, elem buildableElement) {
+	c := elem.component()
+	c.ctx = &Context{app: ctx.app, window: ctx.window, element: elem, parent: ctx}
+	c.signals = signal.NewTracker(func() {
+		if err := updateWidgetState(func() {}, elem); err != nil {
+			c.ctx.ReportError(PhaseNative, elem, err)
+		}
+	})
+}
+
+// buildChildWidget builds the child widget of elem, tracking the signals read.
+// The dependencies of the last build are cleared, and the ones of this build are recorded
+// by [DependOn], so elem is not rebuilt with the inherited widgets it no longer depends on.
+func buildChildWidget(elem buildableElement) (widget Widget) {
+	c := elem.component()
+	c.clearDependencies()
+	c.signals.Run(func() { widget = elem.build() })
+	return
+}
+
+// canBuildFragment returns whether elem can build a [Fragment].
+// The layouter of [ErrorBoundary] lays out its only child.
+func canBuildFragment(elem buildableElement) bool {
+	_, isBoundary := elem.(*errorBoundaryElement)
+	return !isBoundary
+}
+
+// buildComponentElement builds the child of elem, or the children if elem builds a [Fragment].
+func buildComponentElement(elem buildableElement) (Element, error) {
+	if canBuildFragment(elem) {
+		c := elem.component()
+		widget := buildChildWidget(elem)
+		_, c.fragment = widget.(*Fragment)
+		if err := buildChildElements(c.ctx, elem, appendFlattened(nil, widget)); err != nil {
+			return nil, err
+		}
+		elem.didBuild()
+		return elem, nil
+	}
+	childElem, err := guardChild(elem, func() (Element, error) {
+		return buildElementTreeImpl(elem.component().ctx, buildChildWidget(elem))
+	})
+	if err != nil {
+		return nil, err
+	}
+	element_AppendChild(elem, childElem)
+	elem.didBuild()
+	return elem, nil
+}
+
+// updateComponentElement rebuilds the child widget of elem and reconciles.
+// If the new child widget is equal to the old one(see [EqualWidget]),
+// the child is left untouched, and changed is false.
+func updateComponentElement(elem buildableElement) (changed bool, err error) {
+	c := elem.component()
+	c.dirty = false
+	widget := buildChildWidget(elem)
+	if _, isFragment := widget.(*Fragment); c.fragment || isFragment && canBuildFragment(elem) {
+		// The children are reconciled as the ones of a container.
+		if err = updateChildElements(c.ctx, elem, appendFlattened(nil, widget)); err != nil {
+			return
+		}
+		c.fragment = isFragment
+		elem.didBuild()
+		return true, nil
+	}
+	if elem.numChildren() == 0 { // The child is moved away with its GlobalID.
+		childElem, err := guardChild(elem, func() (Element, error) {
+			return buildElementTreeImpl(c.ctx, widget)
+		})
+		if err != nil {
+			return false, err
+		}
+		element_AppendChild(elem, childElem)
+		elem.didBuild()
+		return true, placeNativeTree(c.ctx, elem, 0)
+	}
+	child := elem.child(0)
+	if widgetMatch(child.Widget(), widget) && widgetEqual(child.Widget(), widget) {
+		elem.didBuild()
+		return false, nil
+	}
+	childElem, err := guardChild(elem, func() (Element, error) {
+		return reconcileElementTreeImpl(c.ctx, child, widget)
+	})
+	if err != nil {
+		return
+	}
+	if elem.numChildren() == 0 { // The child is moved away with its GlobalID.
+		element_AppendChild(elem, childElem)
+	} else {
+		element_SetChild(elem, 0, childElem)
+	}
+	elem.didBuild()
+	var recreated []int // see App.checkRecreated
+	if childElem != child {
+		if reflect.TypeOf(child.Widget()) == reflect.TypeOf(widget) {
+			recreated = []int{0}
+		}
+		if err = placeNativeTree(c.ctx, elem, 0); err != nil {
+			return
+		}
+	}
+	c.ctx.app.checkRecreated(elem, recreated)
+	if patchesWanted(c.ctx, elem) {
+		var patches []Patch
+		if childElem == child {
+			patches = []Patch{newPatch(PatchUpdate, widget, 0, 0)}
+		} else {
+			patches = []Patch{newPatch(PatchDestroy, child.Widget(), 0, -1), newPatch(PatchCreate, childElem.Widget(), -1, 0)}
+		}
+		if err = applyPatches(c.ctx, elem, patches); err != nil {
+			return
+		}
+	}
+	return true, nil
+}
+
+// rebuildElement rebuilds the child widget of elem and reconciles.
+// The returned layouter is the layouter of the reconciled child or its nearest child,
+// or nil if the child is unchanged and needs no layout.
+// If a [Fragment] is built at or below elem before or after the rebuild, the returned
+// layouter is the one of the nearest ancestor, which lays out the widgets of the Fragment.
+func rebuildElement(elem buildableElement) (layouter Layouter, err error) {
+	if err = elem.component().ctx.app.checkGoroutine("reconcile"); err != nil {
+		return
+	}
+	fragment := buildsFragment(elem)
+	changed, err := updateComponentElement(elem)
+	if err != nil || !changed {
+		return
+	}
+	if fragment || buildsFragment(elem) {
+		if layouter = ancestorLayouter(elem); layouter == nil {
+			return nil, ErrFragmentNotInContainer
+		}
+		if err = checkFragmentBuilt(layouter.Element()); err != nil {
+			return nil, err
+		}
+		return
+	}
+	return layouterTree(elem.child(0)), nil
+}
+
+// guardChild calls f to build or reconcile the child of elem.
+// If elem is an [ErrorBoundary], the errors and panics of f are caught, see [errorBoundaryElement.guard].
+func guardChild(elem buildableElement, f func() (Element, error)) (Element, error) {
+	if boundary, ok := elem.(*errorBoundaryElement); ok {
+		return boundary.guard(f)
+	}
+	return f()
+}
+
+// addDependency records that e depends on inherited.
+func (e *componentElement) addDependency(inherited *inheritedElement) {
+	if !slices.Contains(e.dependencies, inherited) {
+		e.dependencies = append(e.dependencies, inherited)
+	}
+}
@@ -0,0 +1,145 @@
+package goui
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+	"github.com/mkch/goui/signal"
+)
+
+func TestSignal_RebuildReaders(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	count := signal.New(0)
+	builds := map[string]int{}
+	leaf := func() Widget {
+		return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	}
+	reader := NewStatelessWidget(ValueID("reader"), func(ctx *Context) Widget {
+		builds["reader"]++
+		count.Get()
+		return leaf()
+	})
+	other := NewStatelessWidget(ValueID("other"), func(ctx *Context) Widget {
+		builds["other"]++
+		count.Peek()
+		return leaf()
+	})
+	root := NewStatefulWidget(ValueID("root"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		return &WidgetState{Build: func() Widget {
+			builds["root"]++
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{reader, other}}
+		}}
+	})
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count.Set(1)
+	if !backend.RunPending() {
+		t.Fatal("no frame is posted")
+	}
+	if builds["root"] != 1 || builds["other"] != 1 || builds["reader"] != 2 {
+		t.Fatalf("expected only the reader to be rebuilt, got %v", builds)
+	}
+
+	// Destroyed readers are not rebuilt.
+	if _, _, err = reconcileElementTree(ctx, ctx.window.Root, leaf()); err != nil {
+		t.Fatal(err)
+	}
+	count.Set(2)
+	if backend.RunPending() {
+		t.Fatal("unexpected frame")
+	}
+}
+
+func TestContext_Cancel(t *testing.T) {
+	backend := headless.New()
+	app := newApp(&AppConfig{Debug: &Debug{}, Backend: backend}, backend)
+	contexts := map[string]context.Context{}
+	child := func(name string) Widget {
+		return NewStatelessWidget(ValueID(name), func(ctx *Context) Widget {
+			contexts[name] = ctx.Context()
+			return &nativeWidget{text: name}
+		})
+	}
+	show := true
+	var update UpdateStateFunc
+	var lifecycle []string
+	root := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{
+			Deactivate:  func() { lifecycle = append(lifecycle, "Deactivate") },
+			DestroyData: func() { lifecycle = append(lifecycle, "DestroyData") },
+			Build: func() Widget {
+				children := []Widget{child("kept")}
+				if show {
+					children = append(children, child("removed"))
+				}
+				return &layouterContainer{layouter: &countingLayouter{}, children: children}
+			},
+		}
+	})
+	closed := false
+	if err := app.CreateWindow(Window{ID: ValueID("window"), Root: root, OnClose: func() { closed = true }}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.buildWindows(); err != nil {
+		t.Fatal(err)
+	}
+	if contexts["kept"].Err() != nil || contexts["removed"].Err() != nil {
+		t.Fatalf("contexts are cancelled before destroyed")
+	}
+
+	// Destroyed by reconciliation.
+	if err := update(func() { show = false }); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if contexts["removed"].Err() != context.Canceled {
+		t.Fatalf("context of the removed element is not cancelled")
+	}
+	if contexts["kept"].Err() != nil {
+		t.Fatalf("context of the kept element is cancelled")
+	}
+
+	// Destroyed by closing the window.
+	window := app.windows[ValueID("window")]
+	windowCtx := (&Context{app: app, window: window}).Context()
+	label := window.Root.child(0).child(0).child(0).(*NativeElement).Handle.(*headless.Object)
+	window.Handle.(*headless.Object).Close()
+	if !closed {
+		t.Fatalf("OnClose is not called")
+	}
+	if contexts["kept"].Err() != context.Canceled || windowCtx.Err() != context.Canceled {
+		t.Fatalf("contexts are not cancelled when the window is closed")
+	}
+	if !label.Destroyed || window.Root != nil {
+		t.Fatalf("element tree is not destroyed when the window is closed")
+	}
+	if !slices.Equal(lifecycle, []string{"Deactivate", "DestroyData"}) {
+		t.Fatalf("expected the state to be deactivated and destroyed, got %v", lifecycle)
+	}
+	if app.windows[ValueID("window")] != nil {
+		t.Fatalf("closed window is not removed")
+	}
+}
+
+func TestContext_WindowClosed(t *testing.T) {
+	app := newApp(&AppConfig{Debug: &Debug{}}, nil)
+	w := &window{ID: ValueID("window")}
+	app.closeWindow(w)
+	if (&Context{app: app, window: w}).Context().Err() != context.Canceled {
+		t.Fatalf("context created after the window is closed is not cancelled")
+	}
+}
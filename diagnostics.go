@@ -0,0 +1,74 @@
+package goui
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// DuplicateIDError is returned in debug mode when children of a container have the same ID,
+// and in any mode when a [GlobalID] is used by more than one widget.
+type DuplicateIDError struct {
+	ID ID
+	// The indexes of the children, or -1 for a GlobalID.
+	Index1, Index2 int
+}
+
+func (e *DuplicateIDError) Error() string {
+	if e.Index1 < 0 {
+		return fmt.Sprintf("duplicate GlobalID %v", e.ID)
+	}
+	return fmt.Sprintf("duplicate ID %v of children %d and %d", e.ID, e.Index1, e.Index2)
+}
+
+// checkDuplicateIDs returns a [DuplicateIDError] if debug mode is on and
+// children, the child widgets of a container, have duplicate IDs.
+func (app *App) checkDuplicateIDs(children []Widget) error {
+	if app.debug == nil {
+		return nil
+	}
+	indexes := make(map[ID]int, len(children))
+	for i, child := range children {
+		id := child.WidgetID()
+		if id == nil {
+			continue
+		}
+		if j, ok := indexes[id]; ok {
+			return &DuplicateIDError{ID: id, Index1: j, Index2: i}
+		}
+		indexes[id] = i
+	}
+	return nil
+}
+
+// recreatedWarningThreshold is the number of reconciliations in a row that recreate a child
+// before the warning of [App.checkRecreated].
+const recreatedWarningThreshold = 2
+
+// checkRecreated is called in debug mode after the children of parent are reconciled.
+// Recreated are the indexes of the children recreated in place, that is, built for
+// widgets of the same type as the destroyed old children at the same indexes.
+// A warning with the widget path is logged if a child is recreated by several
+// reconciliations in a row, which is usually caused by an ID changing on every build,
+// such as ValueID(time.Now()).
+func (app *App) checkRecreated(parent Element, recreated []int) {
+	if app.debug == nil {
+		return
+	}
+	last := app.recreated[parent]
+	if len(recreated) == 0 {
+		delete(app.recreated, parent)
+		return
+	}
+	counts := make(map[int]int, len(recreated))
+	for _, i := range recreated {
+		counts[i] = last[i] + 1
+		if counts[i] == recreatedWarningThreshold {
+			slog.Warn("goui: element is recreated on every rebuild, check whether its ID is stable",
+				"path", FormatWidgetPath(widgetPath(parent.child(i))))
+		}
+	}
+	if app.recreated == nil {
+		app.recreated = make(map[Element]map[int]int)
+	}
+	app.recreated[parent] = counts
+}
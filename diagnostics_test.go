@@ -0,0 +1,154 @@
+package goui
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+func TestDuplicateIDs(t *testing.T) {
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}})
+	leaf := func(id string) Widget {
+		return &mockWidget{ID: ValueID(id), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	}
+	var dupErr *DuplicateIDError
+	_, _, err := buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{leaf("a"), leaf("b"), leaf("a")}})
+	if !errors.As(err, &dupErr) || dupErr.ID != ValueID("a") || dupErr.Index1 != 0 || dupErr.Index2 != 2 {
+		t.Fatalf("expected duplicate ID error, got %v", err)
+	}
+
+	root, _, err := buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{leaf("a"), leaf("b")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = reconcileElementTree(ctx, root, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{leaf("b"), leaf("b")}})
+	if !errors.As(err, &dupErr) || dupErr.ID != ValueID("b") {
+		t.Fatalf("expected duplicate ID error, got %v", err)
+	}
+
+	// Not checked if debug mode is off.
+	ctx = newMockContext(&AppConfig{})
+	if _, _, err = buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{leaf("a"), leaf("a")}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDuplicateIDs_Release(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	root, _, err := buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+		&nativeWidget{ID: ValueID("c"), text: "c"},
+		&nativeWidget{ID: ValueID("a"), text: "a1"},
+		&nativeWidget{ID: ValueID("a"), text: "a2"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := root.child(1).(*NativeElement)
+	second := root.child(2).(*NativeElement).Handle.(*headless.Object)
+	// The middle part is matched by ID.
+	if _, _, err = reconcileElementTree(ctx, root, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+		&nativeWidget{ID: ValueID("a"), text: "a"},
+		&nativeWidget{ID: ValueID("b"), text: "b"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if root.child(0) != first {
+		t.Fatalf("the first element of the duplicate ID is not matched")
+	}
+	if !second.Destroyed {
+		t.Fatalf("the other element of the duplicate ID is not destroyed")
+	}
+}
+
+func TestRecreatedWarning(t *testing.T) {
+	var log bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&log, nil)))
+
+	ctx := newHeadlessContext(t)
+	var update UpdateStateFunc
+	builds := 0
+	stateful := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			builds++
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+				// The ID changes on every build.
+				&mockWidget{ID: ValueID(builds), element: &ElementBase{ElementLayouter: &mockLayouter{}}},
+				&mockWidget{ID: ValueID("stable"), element: &ElementBase{ElementLayouter: &mockLayouter{}}},
+			}}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, stateful)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if err = update(func() {}); err != nil {
+			t.Fatal(err)
+		}
+		if err = ctx.app.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(log.String(), "level=WARN"); n != 1 {
+		t.Fatalf("expected 1 warning, got %d:\n%v", n, log.String())
+	}
+	const path = "*goui.statefulWidget/*goui.layouterContainer/*goui.mockWidget[3]"
+	if !strings.Contains(log.String(), path) {
+		t.Fatalf("expected warning with path %v, got:\n%v", path, log.String())
+	}
+}
+
+func TestRecreated_Destroyed(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	var update UpdateStateFunc
+	builds := 0
+	container := true
+	stateful := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			builds++
+			if !container {
+				return &mockWidget{ID: ValueID("leaf"), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+			}
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+				&mockWidget{ID: ValueID(builds), element: &ElementBase{ElementLayouter: &mockLayouter{}}},
+			}}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, stateful)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = update(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.app.recreated) != 1 {
+		t.Fatalf("expected the recreation to be recorded, got %v", ctx.app.recreated)
+	}
+	// The container is destroyed.
+	if err = update(func() { container = false }); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.app.recreated) != 0 {
+		t.Fatalf("expected the recreation to be forgotten, got %v", ctx.app.recreated)
+	}
+}
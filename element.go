@@ -17,13 +17,9 @@ type Element interface {
 	numChildren() int
 	child(n int) Element
 	indexChild(child Element) int
-	// updateChildren updates the children of the element to newChildren.
-	// newChildren is the new slice of children, which may not have their parent set correctly.
-	// unusedChildren contains the children that are no longer used and should be destroyed.
-	updateChildren(newChildren []Element, unusedChildren []Element)
 	destroy()
 
-	// setLayouter sets the layouter of the element. For debug purposes only.
+	// setLayouter sets the wrapped layouter of the element, see [buildElement].
 	setLayouter(layouter Layouter)
 	// setParent is a helper of [element_AppendChild].
 	// The implementation should just set the parent field or some equivalent.
@@ -34,13 +30,18 @@ type Element interface {
 	// setChildInSlice is a helper of [element_SetChild].
 	// The implementation should just set child at index n in the children slice or some equivalent.
 	setChildInSlice(n int, child Element)
+	// setChildrenInSlice is a helper of [element_UpdateChildren].
+	// The implementation should just replace the children slice with children or some equivalent.
+	setChildrenInSlice(children []Element)
 }
 
 // ElementBase implements [Element], and is the building block for other Element types.
 type ElementBase struct {
 	// ElementLayouter is the layouter of the element. Can be nil.
-	// This field is returned by Layouter() method.
+	// This field is returned by Layouter() method, wrapped by the framework
+	// once the element is built.
 	ElementLayouter Layouter
+	layouter        Layouter // The wrapped ElementLayouter, see [buildElement].
 	theWidget       Widget
 	theParent       Element
 	children        []Element
@@ -55,11 +56,14 @@ func (e *ElementBase) SetWidget(ctx *Context, widget Widget) {
 }
 
 func (e *ElementBase) Layouter() Layouter {
+	if e.layouter != nil {
+		return e.layouter
+	}
 	return e.ElementLayouter
 }
 
 func (e *ElementBase) setLayouter(layouter Layouter) {
-	e.ElementLayouter = layouter
+	e.layouter = layouter
 }
 
 func (e *ElementBase) parent() Element {
@@ -78,16 +82,6 @@ func (e *ElementBase) indexChild(child Element) int {
 	return slices.Index(e.children, child)
 }
 
-func (e *ElementBase) updateChildren(newChildren []Element, unusedChildren []Element) {
-	for _, unused := range unusedChildren {
-		unused.destroy()
-	}
-	for _, child := range newChildren {
-		child.setParent(e)
-	}
-	e.children = newChildren
-}
-
 func (e *ElementBase) destroy() {
 	for _, child := range e.children {
 		child.destroy()
@@ -106,6 +100,10 @@ func (e *ElementBase) setChildInSlice(n int, child Element) {
 	e.children[n] = child
 }
 
+func (e *ElementBase) setChildrenInSlice(children []Element) {
+	e.children = children
+}
+
 // element_AppendChild appends child to parent and sets child's parent to parent.
 //
 // We keep this as a package-level function (instead of a method like
@@ -125,24 +123,89 @@ func element_AppendChild(parent, child Element) {
 }
 
 // element_SetChild sets the nth child of parent to child.
+// The replaced child is not destroyed, which is the responsibility of the caller.
 //
 // See [element_AppendChild] for explanation why this is a package-level function.
 func element_SetChild(parent Element, n int, child Element) {
 	if parent.child(n) == child {
 		return
 	}
-	parent.child(n).destroy()
 	parent.setChildInSlice(n, child)
 	child.setParent(parent)
 }
 
+// element_UpdateChildren updates the children of parent to newChildren, and sets their parent to parent.
+//
+// See [element_AppendChild] for explanation why this is a package-level function.
+func element_UpdateChildren(parent Element, newChildren []Element) {
+	parent.setChildrenInSlice(newChildren)
+	for _, child := range newChildren {
+		child.setParent(parent)
+	}
+}
+
+// removeElementTree removes the element tree rooted at elem from the GUI tree.
+// The states in the tree are deactivated from the root down, and then the elements
+// are destroyed, see [WidgetState].
+// If the tree contains elements of GlobalIDs and a reconciliation is in progress,
+// the tree is destroyed after the reconciliation, see [inactiveElements].
+func (app *App) removeElementTree(elem Element) {
+	if deactivateElementTree(elem) && app.inactive.depth > 0 {
+		elem.setParent(nil)
+		app.inactive.trees = append(app.inactive.trees, elem)
+		return
+	}
+	app.destroyElementTree(elem)
+}
+
+// deactivateElementTree deactivates the states in the element tree rooted at elem,
+// parents before children.
+// Whether the tree contains elements of GlobalIDs is returned.
+func deactivateElementTree(elem Element) (global bool) {
+	_, global = elem.Widget().WidgetID().(*GlobalID)
+	if b, ok := elem.(buildableElement); ok {
+		b.component().inactive = true
+	}
+	if statefulElement, ok := elem.(*statefulElement); ok && statefulElement.state != nil {
+		statefulElement.state.deactivate()
+	}
+	for i := range elem.numChildren() {
+		if deactivateElementTree(elem.child(i)) {
+			global = true
+		}
+	}
+	return
+}
+
+// activateElementTree activates the states in the element tree rooted at elem,
+// which is deactivated by [deactivateElementTree], parents before children.
+func activateElementTree(elem Element) {
+	if b, ok := elem.(buildableElement); ok {
+		b.component().inactive = false
+	}
+	if statefulElement, ok := elem.(*statefulElement); ok && statefulElement.state != nil {
+		statefulElement.state.activate()
+	}
+	for i := range elem.numChildren() {
+		activateElementTree(elem.child(i))
+	}
+}
+
 // NativeElement is an [Element] that represents a native GUI widget.
 type NativeElement struct {
 	ElementBase
-	Handle native.Handle
+	// Backend is the native backend that creates Handle.
+	Backend native.Backend
+	Handle  native.Handle
 	// DestroyFunc is called to destroy the native handle.
 	// A nil value means no special destruction is needed.
+	// The error is reported to [AppConfig.OnError].
 	DestroyFunc func(native.Handle) error
+	ctx         *Context // The context the element is built with, see [buildElement].
+}
+
+func (e *NativeElement) nativeElement() *NativeElement {
+	return e
 }
 
 func (e *NativeElement) NativeHandle(*Context) native.Handle {
@@ -150,46 +213,97 @@ func (e *NativeElement) NativeHandle(*Context) native.Handle {
 }
 
 func (e *NativeElement) destroy() {
-	if e.DestroyFunc != nil {
-		e.DestroyFunc(e.Handle)
+	if e.DestroyFunc == nil {
+		return
+	}
+	if err := e.DestroyFunc(e.Handle); err != nil && e.ctx != nil {
+		e.ctx.ReportError(PhaseNative, e, err)
 	}
 }
 
 // buildElementTree builds the element tree for the given widget.
 // The returned layouter is the layouter of the returned element or its nearest child.
 func buildElementTree(ctx *Context, widget Widget) (element Element, layouter Layouter, err error) {
+	if err = ctx.app.checkGoroutine("build"); err != nil {
+		return
+	}
+	ctx.app.beginReconcile() // GlobalIDs are claimed in the build, see [App.claimGlobalID].
+	defer ctx.app.endReconcile()
 	element, err = buildElementTreeImpl(ctx, widget)
 	if err != nil {
 		return
 	}
+	if buildsFragment(element) {
+		ctx.app.removeElementTree(element)
+		err = ErrFragmentNotInContainer
+		ctx.app.failure.unwind(err, widget)
+		return nil, nil, err
+	}
 	layouter = layouterTree(element)
 	return
 }
 
 // buildElementTreeImpl builds the element tree for the given widget.
-func buildElementTreeImpl(ctx *Context, widget Widget) (Element, error) {
+// If the building fails, the partially built element tree is destroyed.
+func buildElementTreeImpl(ctx *Context, widget Widget) (_ Element, err error) {
+	defer func() {
+		if err != nil {
+			ctx.app.failure.unwind(err, widget)
+		}
+	}()
+	if err = ctx.app.claimGlobalID(widget, nil); err != nil {
+		return nil, err
+	}
+	if elem := reclaimElement(ctx, widget); elem != nil {
+		if err = updateElementTree(ctx, elem, widget); err != nil {
+			ctx.app.removeElementTree(elem)
+			return nil, err
+		}
+		return elem, nil
+	}
 	elem, err := widget.CreateElement(ctx)
 	if err != nil {
 		return nil, err
 	}
+	built := false
+	defer func() {
+		if !built { // Error returned or panicking.
+			ctx.app.removeElementTree(elem)
+		}
+	}()
+	if _, err = buildElement(ctx, elem, widget); err != nil {
+		return nil, err
+	}
+	built = true
+	return elem, nil
+}
 
+// buildElement builds the element tree of elem, which is created by widget.
+func buildElement(ctx *Context, elem Element, widget Widget) (Element, error) {
 	if layouter := elem.Layouter(); layouter != nil {
 		layouter.setElement(elem)
-		if ctx.app.debug.LayoutOutlineEnabled() {
+		layouter = &trackingLayouter{Layouter: layouter, stats: ctx.app.layoutCacheStats}
+		elem.setLayouter(layouter)
+		if outline := ctx.app.debug.LayoutOutlineEnabled(); outline || ctx.app.recordLayout {
 			layouter = &debugLayouter{
 				Layouter: layouter,
+				Outline:  outline,
 			}
 			elem.setLayouter(layouter)
 		}
 	}
 
+	if native, ok := elem.(interface{ nativeElement() *NativeElement }); ok {
+		native.nativeElement().ctx = ctx
+	}
 	elem.SetWidget(ctx, widget)
+	registerGlobalID(ctx, elem, widget)
 
 	if statefulWidget, ok := widget.(StatefulWidget); ok {
 		return buildStatefulElement(ctx, elem, statefulWidget)
 	}
-	if statelessWidget, ok := widget.(StatelessWidget); ok {
-		return buildStatelessElement(ctx, elem, statelessWidget)
+	if _, ok := widget.(StatelessWidget); ok {
+		return buildStatelessElement(ctx, elem)
 	}
 	if container, ok := widget.(Container); ok {
 		return buildContainerElement(ctx, elem, container)
@@ -198,35 +312,46 @@ func buildElementTreeImpl(ctx *Context, widget Widget) (Element, error) {
 }
 
 func buildContainerElement(ctx *Context, elem Element, container Container) (Element, error) {
-	numChildren := container.NumChildren()
-	for i := range numChildren {
-		childElem, err := buildElementTreeImpl(ctx, container.Child(i))
+	if err := checkFragmentChild(container); err != nil {
+		return nil, err
+	}
+	if err := buildChildElements(ctx, elem, childWidgets(container)); err != nil {
+		return nil, err
+	}
+	if err := checkFragmentBuilt(elem); err != nil {
+		return nil, err
+	}
+	return elem, nil
+}
+
+// buildChildElements builds the element trees of children, and appends them to elem.
+func buildChildElements(ctx *Context, elem Element, children []Widget) error {
+	if err := ctx.app.checkDuplicateIDs(children); err != nil {
+		return err
+	}
+	for _, child := range children {
+		childElem, err := buildElementTreeImpl(ctx, child)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		element_AppendChild(elem, childElem)
 	}
-	return elem, nil
+	return nil
 }
 
-func buildStatelessElement(ctx *Context, elem Element, statelessWidget StatelessWidget) (Element, error) {
-	childElem, err := buildElementTreeImpl(ctx, statelessWidget.Build(ctx))
-	if err != nil {
-		return nil, err
-	}
-	element_AppendChild(elem, childElem)
-	return elem, nil
+func buildStatelessElement(ctx *Context, elem Element) (Element, error) {
+	statelessElement := elem.(buildableElement)
+	initComponentElement(ctx, statelessElement)
+	return buildComponentElement(statelessElement)
 }
 
 func buildStatefulElement(ctx *Context, elem Element, statefulWidget StatefulWidget) (Element, error) {
 	statefulElement := elem.(*statefulElement)
-	statefulElement.state = statefulWidget.CreateState(ctx, func(f func()) error { return updateWidgetState(f, ctx, statefulElement) })
-	childElem, err := buildElementTreeImpl(ctx, statefulElement.state.Build())
-	if err != nil {
-		return nil, err
-	}
-	element_AppendChild(elem, childElem)
-	return elem, nil
+	initComponentElement(ctx, statefulElement)
+	statefulElement.state = statefulWidget.CreateState(statefulElement.ctx, func(f func()) error { return updateWidgetState(f, statefulElement) })
+	statefulElement.state.initState()
+	statefulElement.dependenciesChanged = true
+	return buildComponentElement(statefulElement)
 }
 
 // updateElementTree is a helper of [reconcileElementTree] that performs the in-place update.
@@ -234,58 +359,91 @@ func buildStatefulElement(ctx *Context, elem Element, statefulWidget StatefulWid
 // The elem will be updated to hold widget.
 // If any error occurs during the update, the error is returned.
 func updateElementTree(ctx *Context, elem Element, widget Widget) (err error) {
+	defer func() {
+		if err != nil {
+			ctx.app.failure.unwind(err, widget)
+		}
+	}()
+	if err = ctx.app.claimGlobalID(widget, elem); err != nil {
+		return
+	}
+	if widgetEqual(elem.Widget(), widget) {
+		return nil
+	}
 	elem.SetWidget(ctx, widget)
+	if layouter := elem.Layouter(); layouter != nil {
+		layouter.layouterBase().needsLayout = true // The cached size is out of date.
+	}
 	if container, ok := widget.(Container); ok {
 		return updateContainerElement(ctx, elem, container)
 	}
 	if _, ok := widget.(StatefulWidget); ok {
-		return updateStatefulWidget(ctx, elem)
+		_, err = updateComponentElement(elem.(*statefulElement))
+		return
 	}
-	if statelessWidget, ok := widget.(StatelessWidget); ok {
-		return updateStatelessWidget(ctx, elem, statelessWidget)
+	if _, ok := widget.(StatelessWidget); ok {
+		_, err = updateComponentElement(elem.(buildableElement))
+		return
 	}
 	return nil
 }
 
-// updateStatelessWidget updates the stateless element elem to hold the new stateless widget.
-func updateStatelessWidget(ctx *Context, elem Element, statelessWidget StatelessWidget) error {
-	childElem, err := reconcileElementTreeImpl(ctx, elem.child(0), statelessWidget.Build(ctx))
-	if err != nil {
+// updateContainerElement updates the container element to hold the new container widget.
+func updateContainerElement(ctx *Context, element Element, container Container) error {
+	if err := checkFragmentChild(container); err != nil {
 		return err
 	}
-	element_SetChild(elem, 0, childElem)
-	return nil
-}
-
-// updateStatefulWidget updates the stateful element elem to hold the new stateful widget.
-func updateStatefulWidget(ctx *Context, elem Element) error {
-	statefulElement := elem.(*statefulElement)
-	// rebuild the child widget and reconcile.
-	childElem, err := reconcileElementTreeImpl(
-		ctx,
-		statefulElement.child(0),
-		statefulElement.state.Build(),
-	)
-	if err != nil {
+	if err := updateChildElements(ctx, element, childWidgets(container)); err != nil {
 		return err
 	}
-	element_SetChild(elem, 0, childElem)
-	return nil
+	return checkFragmentBuilt(element)
 }
 
-// updateContainerElement updates the container element to hold the new container widget.
-func updateContainerElement(ctx *Context, element Element, container Container) error {
-	var newChildren = make([]Element, container.NumChildren()) // the updated children
+// updateChildElements reconciles the children of element to match the new child widgets.
+// If the update fails, the new elements are destroyed, and the old children are kept.
+// The performed operations are passed to [applyPatches] if wanted.
+func updateChildElements(ctx *Context, element Element, children []Widget) error {
+	if err := ctx.app.checkDuplicateIDs(children); err != nil {
+		return err
+	}
+	var newChildren = make([]Element, len(children)) // the updated children
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		// Error returned or panicking.
+		for _, child := range newChildren {
+			if child != nil && element.indexChild(child) < 0 {
+				ctx.app.removeElementTree(child)
+			}
+		}
+	}()
 
 	numElem := element.numChildren()
-	numWidget := container.NumChildren()
+	numWidget := len(children)
+	// The old children. Children with GlobalIDs may be moved away during the update.
+	oldChildren := make([]Element, numElem)
+	for i := range oldChildren {
+		oldChildren[i] = element.child(i)
+	}
+
+	var patches []Patch // nil if not wanted
+	if patchesWanted(ctx, element) {
+		patches = make([]Patch, 0, max(numElem, numWidget))
+	}
+	addPatch := func(op PatchOp, widget Widget, oldIndex, newIndex int) {
+		if patches != nil {
+			patches = append(patches, newPatch(op, widget, oldIndex, newIndex))
+		}
+	}
 
 	// Phase 1: Top-down match
 
 	var topDownCount = 0 // number of matched elements(widgets) from the top
 	for i := 0; i < min(numElem, numWidget); i++ {
-		widget := container.Child(i)
-		elem := element.child(i)
+		widget := children[i]
+		elem := oldChildren[i]
 		if !widgetMatch(widget, elem.Widget()) {
 			break
 		}
@@ -294,6 +452,7 @@ func updateContainerElement(ctx *Context, element Element, container Container)
 			return err
 		}
 		newChildren[i] = elem
+		addPatch(PatchUpdate, widget, i, i)
 		topDownCount++
 	}
 
@@ -303,8 +462,8 @@ func updateContainerElement(ctx *Context, element Element, container Container)
 	for i := 0; numElem-i > topDownCount && numWidget-i > topDownCount; i++ {
 		widgetIndex := numWidget - 1 - i
 		elemIndex := numElem - 1 - i
-		widget := container.Child(widgetIndex)
-		elem := element.child(elemIndex)
+		widget := children[widgetIndex]
+		elem := oldChildren[elemIndex]
 		if !widgetMatch(widget, elem.Widget()) {
 			break
 		}
@@ -313,6 +472,7 @@ func updateContainerElement(ctx *Context, element Element, container Container)
 			return err
 		}
 		newChildren[widgetIndex] = elem
+		addPatch(PatchUpdate, widget, elemIndex, widgetIndex)
 		bottomUpCount++
 	}
 
@@ -320,47 +480,130 @@ func updateContainerElement(ctx *Context, element Element, container Container)
 	//   Widgets and elements with IDs are matched by ID.
 	//   Widgets without IDs and unmatched widgets are treated as new, and new elements are created for them.
 	//   Elements without IDs and unmatched elements are destroyed.
+	//   Of the matched elements, those in the longest increasing subsequence of old indexes
+	//   keep their relative order, and the others are moved.
 
-	var unmatchedKeyedElements map[ID]Element // old elements with ID in the middle
+	var unmatchedKeyedElements map[ID]int     // indexes of old elements with ID in the middle
 	var unusedElements []Element              // old elements without ID in the middle
 	if topDownCount+bottomUpCount < numElem { // if there are old elements left
-		unmatchedKeyedElements = make(map[ID]Element, numElem-topDownCount-bottomUpCount)
+		unmatchedKeyedElements = make(map[ID]int, numElem-topDownCount-bottomUpCount)
 		// collect old elements with ID
 		for i := topDownCount; i <= numElem-1-bottomUpCount; i++ {
-			elem := element.child(i)
+			elem := oldChildren[i]
 			id := elem.Widget().WidgetID()
-			if id != nil {
-				unmatchedKeyedElements[id] = elem
+			// Of the elements with duplicate IDs, which are not reported if debug mode is off,
+			// the first one is matched and the others are destroyed.
+			if _, duplicate := unmatchedKeyedElements[id]; id != nil && !duplicate {
+				unmatchedKeyedElements[id] = i
 			} else {
 				unusedElements = append(unusedElements, elem)
+				addPatch(PatchDestroy, elem.Widget(), i, -1)
 			}
 		}
 	}
+	var created []int                // new indexes of the created elements
+	var matchedNew, matchedOld []int // new and old indexes of the elements updated in place
 	// Process widgets in the middle part
 	for i := topDownCount; i <= numWidget-1-bottomUpCount; i++ {
-		widget := container.Child(i)
+		widget := children[i]
 		widgetID := widget.WidgetID()
-		matchedElem := unmatchedKeyedElements[widgetID] // no need to handle nil ID here
 		var updatedElem Element
 		var err error
-		if matchedElem == nil {
+		if elemIndex, ok := unmatchedKeyedElements[widgetID]; !ok { // no need to handle nil ID here
 			updatedElem, err = buildElementTreeImpl(ctx, widget)
+			addPatch(PatchCreate, widget, -1, i)
+			created = append(created, i)
 		} else {
+			matchedElem := oldChildren[elemIndex]
+			oldWidget := matchedElem.Widget()
 			updatedElem, err = reconcileElementTreeImpl(ctx, matchedElem, widget)
 			delete(unmatchedKeyedElements, widgetID)
+			if updatedElem != matchedElem { // Types do not match.
+				addPatch(PatchDestroy, oldWidget, elemIndex, -1)
+				addPatch(PatchCreate, widget, -1, i)
+				created = append(created, i)
+			} else {
+				matchedNew = append(matchedNew, i)
+				matchedOld = append(matchedOld, elemIndex)
+			}
 		}
 		if err != nil {
 			return err
 		}
 		newChildren[i] = updatedElem
 	}
+	placed := slices.Clone(created) // new indexes of the created and moved elements
+	stay := longestIncreasingSubsequence(matchedOld)
+	for j, i := range matchedNew {
+		if len(stay) > 0 && stay[0] == j {
+			stay = stay[1:]
+			addPatch(PatchUpdate, children[i], matchedOld[j], i)
+		} else {
+			addPatch(PatchMove, children[i], matchedOld[j], i)
+			placed = append(placed, i)
+		}
+	}
 	// Collect unused old elements
-	for _, unusedElem := range unmatchedKeyedElements {
+	for _, i := range unmatchedKeyedElements {
+		unusedElem := oldChildren[i]
+		if unusedElem.parent() != element {
+			continue // Moved to another parent with its GlobalID.
+		}
 		unusedElements = append(unusedElements, unusedElem)
+		addPatch(PatchDestroy, unusedElem.Widget(), i, -1)
+	}
+	var recreated []int // see App.checkRecreated
+	if ctx.app.debug != nil {
+		for _, i := range created {
+			if i <= numElem-1-bottomUpCount && !slices.Contains(matchedOld, i) &&
+				reflect.TypeOf(oldChildren[i].Widget()) == reflect.TypeOf(children[i]) {
+				recreated = append(recreated, i)
+			}
+		}
 	}
 	// Update the element
-	element.updateChildren(newChildren, unusedElements)
-	return nil
+	committed = true
+	for _, unused := range unusedElements {
+		ctx.app.removeElementTree(unused)
+	}
+	element_UpdateChildren(element, newChildren)
+	ctx.app.checkRecreated(element, recreated)
+	slices.Sort(placed)
+	for _, i := range placed {
+		if err := placeNativeTree(ctx, element, i); err != nil {
+			return err
+		}
+	}
+	return applyPatches(ctx, element, patches)
+}
+
+// longestIncreasingSubsequence returns the indexes of a longest strictly increasing
+// subsequence of s in ascending order.
+func longestIncreasingSubsequence(s []int) []int {
+	// tails[k] is the index of the smallest tail of the increasing subsequences of length k+1.
+	var tails []int
+	prev := make([]int, len(s)) // index of the previous element in the subsequence, or -1
+	for i, v := range s {
+		k, _ := slices.BinarySearchFunc(tails, v, func(j, v int) int { return s[j] - v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	if len(tails) == 0 {
+		return nil
+	}
+	result := make([]int, len(tails))
+	for k, i := len(tails)-1, tails[len(tails)-1]; k >= 0; k, i = k-1, prev[i] {
+		result[k] = i
+	}
+	return result
 }
 
 // widgetMatch returns whether widget1 and widget2 are considered the same which
@@ -374,9 +617,14 @@ func widgetMatch(widget1, widget2 Widget) bool {
 // The reconciled element and any error occurred during the process are returned.
 func reconcileElementTreeImpl(ctx *Context, element Element, widget Widget) (reconciled Element, err error) {
 	// Widgets do not match, recreate the entire element tree.
+	// The new tree is built before the old one is removed, so the old tree
+	// is kept intact if the building fails.
 	if !widgetMatch(element.Widget(), widget) {
-		element.destroy()
-		return buildElementTreeImpl(ctx, widget)
+		if reconciled, err = buildElementTreeImpl(ctx, widget); err != nil {
+			return
+		}
+		ctx.app.removeElementTree(element)
+		return
 	}
 	// Widgets match, update the widget of the element.
 	err = updateElementTree(ctx, element, widget)
@@ -390,10 +638,19 @@ func reconcileElementTreeImpl(ctx *Context, element Element, widget Widget) (rec
 // The returned reconciled is the reconciled element(maybe the same as elem).
 // The returned layouter is the layouter of the updated element or its nearest child.
 func reconcileElementTree(ctx *Context, elem Element, widget Widget) (reconciled Element, layouter Layouter, err error) {
+	if err = ctx.app.checkGoroutine("reconcile"); err != nil {
+		return
+	}
+	ctx.app.beginReconcile()
+	defer ctx.app.endReconcile()
 	reconciled, err = reconcileElementTreeImpl(ctx, elem, widget)
 	if err != nil {
 		return
 	}
+	if buildsFragment(reconciled) {
+		err = ErrFragmentNotInContainer
+		return
+	}
 	layouter = layouterTree(reconciled)
 	return
 }
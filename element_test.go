@@ -97,7 +97,7 @@ func TestBuildElementTree_WidgetWithLayouter(t *testing.T) {
 	if widget := resultElem.Widget(); widget != mockWidget {
 		t.Errorf("element widget not set correctly")
 	}
-	if layouter != mockLayouter {
+	if layouter.(*trackingLayouter).Layouter != mockLayouter {
 		t.Errorf("expected layouter to be returned")
 	}
 	if mockLayouter.Element() != mockElement {
@@ -134,7 +134,7 @@ func TestBuildElementTree_StatefulWidget(t *testing.T) {
 	if elem.child(0).Widget().WidgetID() != childWidget.WidgetID() {
 		t.Errorf("child widget not set correctly")
 	}
-	if layouter != mockLayouter {
+	if layouter.(*trackingLayouter).Layouter != mockLayouter {
 		t.Errorf("wrong layouter returned")
 	}
 }
@@ -168,7 +168,7 @@ func TestBuildElementTree_StatelessWidget(t *testing.T) {
 	if elem.child(0).Widget().WidgetID() != childWidget.WidgetID() {
 		t.Errorf("child widget not set correctly")
 	}
-	if layouter != mockLayouter {
+	if layouter.(*trackingLayouter).Layouter != mockLayouter {
 		t.Errorf("wrong layouter returned")
 	}
 }
@@ -235,10 +235,10 @@ func TestBuildElementTree_Container(t *testing.T) {
 	if len(children) != 2 {
 		t.Errorf("layouter should have 2 children, got %d", len(children))
 	}
-	if children[0] != layouter1 {
+	if children[0].(*trackingLayouter).Layouter != layouter1 {
 		t.Errorf("first child layouter not set correctly")
 	}
-	if children[1] != layouter2 {
+	if children[1].(*trackingLayouter).Layouter != layouter2 {
 		t.Errorf("second child layouter not set correctly")
 	}
 }
@@ -541,3 +541,21 @@ func TestUpdateElementTree_Remove(t *testing.T) {
 		t.Fatalf("child layouters not updated correctly")
 	}
 }
+
+func TestLongestIncreasingSubsequence(t *testing.T) {
+	for _, test := range []struct {
+		s    []int
+		want []int
+	}{
+		{nil, nil},
+		{[]int{3}, []int{0}},
+		{[]int{1, 2, 3}, []int{0, 1, 2}},
+		{[]int{3, 2, 1}, []int{2}},
+		{[]int{4, 1, 5, 2, 3}, []int{1, 3, 4}},
+		{[]int{2, 6, 3, 4, 1, 5}, []int{0, 2, 3, 5}},
+	} {
+		if got := longestIncreasingSubsequence(test.s); !slices.Equal(got, test.want) {
+			t.Errorf("longestIncreasingSubsequence(%v) = %v, want %v", test.s, got, test.want)
+		}
+	}
+}
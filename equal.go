@@ -0,0 +1,49 @@
+package goui
+
+// EqualWidget is implemented by widgets that can tell whether they are
+// unchanged from an old widget.
+// When a widget is rebuilt and is equal to the old one, the update of the
+// element is skipped: SetWidget is not called, the whole subtree is left
+// untouched, and the layout is not redone.
+type EqualWidget interface {
+	Widget
+	// Equal reports whether the widget is equal to old, which has the same type and ID.
+	Equal(old Widget) bool
+}
+
+// widgetEqual reports whether widget is equal to old, which matches widget.
+// See [EqualWidget].
+func widgetEqual(old, widget Widget) bool {
+	if eq, ok := widget.(EqualWidget); ok {
+		return eq.Equal(old)
+	}
+	return false
+}
+
+// Const is a widget whose child never changes.
+// Once built, the element tree of Widget is not updated when the parent is rebuilt,
+// although the stateful widgets in it can still rebuild themselves.
+// Use Const for the parts of a widget tree which do not depend on anything.
+type Const struct {
+	ID     ID
+	Widget Widget
+}
+
+func (w *Const) WidgetID() ID {
+	return w.ID
+}
+
+func (w *Const) CreateElement(ctx *Context) (Element, error) {
+	return createStatelessElement(ctx), nil
+}
+
+func (w *Const) Build(ctx *Context) Widget {
+	return w.Widget
+}
+
+func (w *Const) Exclusive(StatelessWidget) { /*Nop*/ }
+
+// Equal returns true, so the element tree is never updated.
+func (w *Const) Equal(old Widget) bool {
+	return true
+}
@@ -0,0 +1,101 @@
+package goui
+
+import "testing"
+
+// equalWidget is a widget equal to the old one if value is unchanged.
+type equalWidget struct {
+	mockWidget
+	value int
+}
+
+func (w *equalWidget) Equal(old Widget) bool {
+	return old.(*equalWidget).value == w.value
+}
+
+func TestEqualWidget(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	var update UpdateStateFunc
+	value := 0
+	stateful := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			return &equalWidget{mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}, value}
+		}}
+	})
+	rootLayouter := &countingLayouter{}
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, &layouterContainer{layouter: rootLayouter, children: []Widget{stateful}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = layoutWindow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	elem := ctx.window.Root.child(0).child(0)
+	old := elem.Widget()
+
+	// Equal: neither updated nor relaid out.
+	if err = update(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if elem.Widget() != old {
+		t.Fatalf("equal widget is updated")
+	}
+	if rootLayouter.layouts != 1 {
+		t.Fatalf("expected no relayout, got %d layouts", rootLayouter.layouts)
+	}
+
+	// Not equal.
+	if err = update(func() { value = 1 }); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.window.Root.child(0).child(0) != elem {
+		t.Fatalf("element is recreated")
+	}
+	if elem.Widget() == old {
+		t.Fatalf("changed widget is not updated")
+	}
+	if rootLayouter.layouts != 2 {
+		t.Fatalf("expected a relayout, got %d layouts", rootLayouter.layouts)
+	}
+}
+
+func TestConst(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	var update UpdateStateFunc
+	var parentBuilds, constBuilds int
+	stateful := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			parentBuilds++
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+				&Const{Widget: NewStatelessWidget(nil, func(ctx *Context) Widget {
+					constBuilds++
+					return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+				})},
+			}}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, stateful)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err = update(func() {}); err != nil {
+			t.Fatal(err)
+		}
+		if err = ctx.app.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if parentBuilds != 3 || constBuilds != 1 {
+		t.Fatalf("expected 3 parent builds and 1 const build, got %d and %d", parentBuilds, constBuilds)
+	}
+}
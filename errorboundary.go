@@ -0,0 +1,207 @@
+package goui
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error of a recovered panic.
+type PanicError struct {
+	Value any    // The value passed to panic.
+	Stack []byte // The stack trace of the panicking goroutine.
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// catchErrors calls f and returns its results. If f panics, the panic is recovered
+// and returned as a [PanicError].
+func catchErrors[T any](f func() (T, error)) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// ErrorBoundary is a widget that catches the errors and panics while building,
+// reconciling or laying out the widgets below it.
+// When an error is caught, the subtree of Widget is destroyed and the widget built
+// by Fallback is shown instead, until retry is called.
+// Errors of the fallback widget are not caught, but passed to the outer ErrorBoundary.
+// ErrorBoundary has no visual of its own, the child is laid out as if it were not wrapped.
+type ErrorBoundary struct {
+	ID     ID
+	Widget Widget
+	// Fallback builds the widget shown in place of Widget after err is caught.
+	// A panic is caught as a [*PanicError]. Calling retry rebuilds Widget in the next frame.
+	// Fallback can't be nil.
+	Fallback func(ctx *Context, err error, retry func()) Widget
+}
+
+func (w *ErrorBoundary) WidgetID() ID {
+	return w.ID
+}
+
+func (w *ErrorBoundary) CreateElement(ctx *Context) (Element, error) {
+	return &errorBoundaryElement{
+		componentElement: componentElement{
+			ElementBase: ElementBase{ElementLayouter: &errorBoundaryLayouter{}},
+		},
+	}, nil
+}
+
+func (w *ErrorBoundary) Build(ctx *Context) Widget {
+	return w.Widget
+}
+
+func (w *ErrorBoundary) Exclusive(StatelessWidget) { /*Nop*/ }
+
+type errorBoundaryElement struct {
+	componentElement
+	err error // The caught error, or nil if Widget is shown.
+	// The layout error of Widget, which is caught when e is rebuilt in the next frame.
+	// A rebuild by an ancestor lays out Widget again, and clears it.
+	layoutErr error
+}
+
+func (e *errorBoundaryElement) build() Widget {
+	boundary := e.Widget().(*ErrorBoundary)
+	e.layoutErr = nil
+	if e.err != nil {
+		return boundary.Fallback(e.ctx, e.err, e.retry)
+	}
+	return boundary.Widget
+}
+
+// retry clears the error and rebuilds Widget in the next frame.
+func (e *errorBoundaryElement) retry() {
+	if err := updateWidgetState(func() { e.err = nil }, e); err != nil {
+		e.ctx.ReportError(PhaseNative, e, err)
+	}
+}
+
+// guard calls f to build or reconcile the child of e.
+// If f fails while Widget is shown, the child is destroyed, and the fallback is built instead.
+func (e *errorBoundaryElement) guard(f func() (Element, error)) (Element, error) {
+	child, err := catchErrors(f)
+	if err == nil {
+		return child, nil
+	}
+	if e.err != nil {
+		return nil, err // The fallback fails.
+	}
+	return e.fail(err)
+}
+
+// fail destroys the child of e, if any, and builds the fallback for err.
+// The destroyed child is left in place and should be replaced with the returned element.
+func (e *errorBoundaryElement) fail(err error) (Element, error) {
+	e.err = err
+	if e.numChildren() > 0 {
+		e.ctx.app.removeElementTree(e.child(0))
+	}
+	return buildElementTreeImpl(e.ctx, buildChildWidget(e))
+}
+
+// catch handles err of a descendant, which happened outside the building of e,
+// by showing the fallback. It returns err if the fallback is shown already.
+func (e *errorBoundaryElement) catch(err error) error {
+	if e.err != nil {
+		return err
+	}
+	child, err := e.fail(err)
+	if err != nil {
+		return err
+	}
+	if e.numChildren() == 0 { // The child is moved away with its GlobalID.
+		element_AppendChild(e, child)
+	} else {
+		element_SetChild(e, 0, child)
+	}
+	return placeNativeTree(e.ctx, e, 0)
+}
+
+// nearestErrorBoundary returns the nearest ErrorBoundary above elem, or nil if not found.
+func nearestErrorBoundary(elem buildableElement) *errorBoundaryElement {
+	for ctx := elem.component().ctx.parent; ctx != nil; ctx = ctx.parent {
+		if boundary, ok := ctx.element.(*errorBoundaryElement); ok {
+			return boundary
+		}
+	}
+	return nil
+}
+
+// errorBoundaryLayouter lays out the child of an ErrorBoundary as if it were not wrapped.
+// If the layout fails, the ErrorBoundary is marked dirty to show the fallback in the next frame,
+// because the element tree can't be reconciled while it is laid out. Until then, the failed
+// child is skipped, and the ErrorBoundary takes the minimum size.
+type errorBoundaryLayouter struct {
+	LayouterBase
+}
+
+func (l *errorBoundaryLayouter) Layout(ctx *Context, constraints Constraints) (Size, error) {
+	e := l.Element().(*errorBoundaryElement)
+	for child := range l.Children() {
+		if e.layoutErr != nil {
+			break
+		}
+		size, err := catchErrors(func() (Size, error) { return child.Layout(ctx, constraints) })
+		if err == nil {
+			return size, nil
+		}
+		if e.err != nil {
+			return Size{}, err // The fallback fails.
+		}
+		if err := e.ctx.app.markNeedsBuild(e); err != nil {
+			return Size{}, err
+		}
+		e.layoutErr = err
+	}
+	return constraints.MinSize(), nil
+}
+
+func (l *errorBoundaryLayouter) PositionAt(x, y int) error {
+	if l.Element().(*errorBoundaryElement).layoutErr != nil {
+		return nil
+	}
+	for child := range l.Children() {
+		return child.PositionAt(x, y)
+	}
+	return nil
+}
+
+// rebuildElementGuarded is [rebuildElement], but the errors and panics are caught by
+// the nearest ErrorBoundary above elem, if any. If the boundary fails to show
+// the fallback, the error is passed to the outer one.
+// If elem is an ErrorBoundary whose child failed to lay out, the fallback is shown instead.
+func rebuildElementGuarded(elem buildableElement) (Layouter, error) {
+	var err error
+	boundary := nearestErrorBoundary(elem)
+	if failed, ok := elem.(*errorBoundaryElement); ok && failed.layoutErr != nil {
+		failed.dirty = false
+		err, failed.layoutErr = failed.layoutErr, nil
+		boundary = failed
+	} else if boundary == nil {
+		return rebuildElement(elem)
+	} else {
+		var layouter Layouter
+		if layouter, err = catchErrors(func() (Layouter, error) { return rebuildElement(elem) }); err == nil {
+			return layouter, nil
+		}
+	}
+	for ; boundary != nil; boundary = nearestErrorBoundary(boundary) {
+		if err = boundary.catch(err); err == nil {
+			return layouterTree(boundary.child(0)), nil
+		}
+	}
+	return nil, err
+}
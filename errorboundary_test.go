@@ -0,0 +1,183 @@
+package goui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+// errorLayouter is a layouter which fails with err.
+type errorLayouter struct {
+	LayouterBase
+	err error
+}
+
+func (l *errorLayouter) Layout(ctx *Context, constraints Constraints) (Size, error) {
+	return Size{}, l.err
+}
+
+func (l *errorLayouter) PositionAt(x, y int) error {
+	return nil
+}
+
+func TestErrorBoundary_Build(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	createErr := errors.New("create error")
+	var caught error
+	var retry func()
+	fail := true
+	fallback := &mockWidget{ID: ValueID("fallback"), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	var sibling *countingDestroyElement
+	boundary := &ErrorBoundary{
+		Widget: NewStatelessWidget(nil, func(ctx *Context) Widget {
+			sibling = &countingDestroyElement{ElementBase: ElementBase{ElementLayouter: &mockLayouter{}}}
+			var broken Widget = &mockWidget{createError: createErr}
+			if !fail {
+				broken = &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+			}
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+				&mockWidget{element: sibling},
+				broken,
+			}}
+		}),
+		Fallback: func(ctx *Context, err error, r func()) Widget {
+			caught, retry = err, r
+			return fallback
+		},
+	}
+
+	root, _, err := buildElementTree(ctx, boundary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(caught, createErr) {
+		t.Fatalf("expected %v to be caught, got %v", createErr, caught)
+	}
+	if root.child(0).Widget() != fallback {
+		t.Fatalf("fallback is not shown")
+	}
+	if sibling.destroyed != 1 {
+		t.Fatalf("partially built sibling destroyed %d times", sibling.destroyed)
+	}
+
+	fail = false
+	retry()
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := root.child(0).child(0).Widget().(*layouterContainer); !ok {
+		t.Fatalf("Widget is not shown after retry")
+	}
+}
+
+func TestErrorBoundary_Panic(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	var update UpdateStateFunc
+	shouldPanic := false
+	var caught error
+	boundary := &ErrorBoundary{
+		Widget: NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+			update = updateState
+			return &WidgetState{Build: func() Widget {
+				if shouldPanic {
+					panic("build panic")
+				}
+				return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+			}}
+		}),
+		Fallback: func(ctx *Context, err error, retry func()) Widget {
+			caught = err
+			return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		},
+	}
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, boundary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The descendant is rebuilt alone, outside the building of the boundary.
+	if err = update(func() { shouldPanic = true }); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	var panicErr *PanicError
+	if !errors.As(caught, &panicErr) || panicErr.Value != "build panic" {
+		t.Fatalf("expected the panic to be caught, got %v", caught)
+	}
+}
+
+func TestErrorBoundary_Layout(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	layoutErr := errors.New("layout error")
+	fallbackLayouter := &countingLayouter{}
+	var retryWidget func()
+	boundary := &ErrorBoundary{
+		Widget: &mockWidget{element: &ElementBase{ElementLayouter: &errorLayouter{err: layoutErr}}},
+		Fallback: func(ctx *Context, err error, retry func()) Widget {
+			if !errors.Is(err, layoutErr) {
+				t.Fatalf("expected %v, got %v", layoutErr, err)
+			}
+			retryWidget = retry
+			return &mockWidget{element: &ElementBase{ElementLayouter: fallbackLayouter}}
+		},
+	}
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, boundary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = layoutWindow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The fallback is built in the next frame, not while the window is laid out.
+	if fallbackLayouter.layouts != 0 {
+		t.Fatalf("expected the fallback not to be laid out yet, got %d layouts", fallbackLayouter.layouts)
+	}
+	if !ctx.app.backend.(*headless.Backend).RunPending() {
+		t.Fatal("expected a frame to be scheduled")
+	}
+	if fallbackLayouter.layouts != 1 {
+		t.Fatalf("expected the fallback to be laid out once, got %d", fallbackLayouter.layouts)
+	}
+
+	// Widget fails again while laid out in a frame, and the fallback is shown in the next one,
+	// which RunPending runs as well.
+	boundary.Widget = &mockWidget{ID: ValueID("widget"), element: &ElementBase{ElementLayouter: &errorLayouter{err: layoutErr}}}
+	retryWidget()
+	if !ctx.app.backend.(*headless.Backend).RunPending() {
+		t.Fatal("expected a frame to be scheduled")
+	}
+	if fallbackLayouter.layouts != 2 {
+		t.Fatalf("expected the fallback to be laid out twice, got %d", fallbackLayouter.layouts)
+	}
+
+	// Errors of the fallback are not caught.
+	boundary.Fallback = func(ctx *Context, err error, retry func()) Widget {
+		return &mockWidget{element: &ElementBase{ElementLayouter: &errorLayouter{err: layoutErr}}}
+	}
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, boundary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = layoutWindow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); !errors.Is(err, layoutErr) {
+		t.Fatalf("expected %v, got %v", layoutErr, err)
+	}
+}
+
+// countingDestroyElement counts the calls of destroy.
+type countingDestroyElement struct {
+	ElementBase
+	destroyed int
+}
+
+func (e *countingDestroyElement) destroy() {
+	e.destroyed++
+	e.ElementBase.destroy()
+}
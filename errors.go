@@ -0,0 +1,147 @@
+package goui
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Phase is the phase of the framework in which an error occurs.
+type Phase int
+
+const (
+	PhaseBuild     Phase = iota // Building element trees of new widgets.
+	PhaseReconcile              // Updating element trees to match rebuilt widgets.
+	PhaseLayout                 // Laying out element trees.
+	PhaseNative                 // Calling the native backend.
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseBuild:
+		return "build"
+	case PhaseReconcile:
+		return "reconcile"
+	case PhaseLayout:
+		return "layout"
+	case PhaseNative:
+		return "native"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+}
+
+// Error is a framework error passed to [AppConfig.OnError].
+type Error struct {
+	Phase Phase
+	// Path is the widgets from the root of the window down to the widget where
+	// the error occurs. For layout errors, Path may end at an ancestor of the
+	// failing widget, the one the layout starts from.
+	Path []Widget
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v error at %v: %v", e.Phase, FormatWidgetPath(e.Path), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FormatWidgetPath formats path as the types of the widgets separated by "/".
+// The IDs of the widgets, if any, are enclosed in brackets.
+func FormatWidgetPath(path []Widget) string {
+	var b strings.Builder
+	for i, widget := range path {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		fmt.Fprintf(&b, "%T", widget)
+		if id := widget.WidgetID(); id != nil {
+			fmt.Fprintf(&b, "[%v]", id)
+		}
+	}
+	return b.String()
+}
+
+// defaultOnError is the default [AppConfig.OnError], which logs err with [slog].
+func defaultOnError(ctx *Context, err error) {
+	if e, ok := err.(*Error); ok {
+		slog.Error("goui: "+e.Err.Error(), "phase", e.Phase, "path", FormatWidgetPath(e.Path))
+		return
+	}
+	slog.Error("goui: " + err.Error())
+}
+
+// widgetPath returns the widgets from the root down to elem, or nil if elem is nil.
+func widgetPath(elem Element) (path []Widget) {
+	for ; elem != nil; elem = elem.parent() {
+		path = append(path, elem.Widget())
+	}
+	slices.Reverse(path)
+	return
+}
+
+// failure records the widgets an error is returned through while building
+// or reconciling, from the innermost out.
+type failure struct {
+	err     error
+	widgets []Widget
+}
+
+// unwind records that err is returned through widget.
+func (f *failure) unwind(err error, widget Widget) {
+	if !sameError(f.err, err) {
+		f.err = err
+		f.widgets = nil
+	}
+	f.widgets = append(f.widgets, widget)
+}
+
+// take returns the recorded widgets of err, outermost first, and resets f.
+func (f *failure) take(err error) (widgets []Widget) {
+	if sameError(f.err, err) {
+		widgets = f.widgets
+		slices.Reverse(widgets)
+	}
+	f.err = nil
+	f.widgets = nil
+	return
+}
+
+// sameError reports whether a and b are the same error value.
+func sameError(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.ValueOf(a).Comparable() && a == b
+}
+
+// newError returns err as an [*Error] of phase occurred below elem.
+// Elem can be nil if the error occurs while building the root of a window.
+// If err is an *Error already, it is returned as is.
+func (app *App) newError(phase Phase, elem Element, err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	path := widgetPath(elem)
+	if phase == PhaseBuild || phase == PhaseReconcile {
+		path = append(path, app.failure.take(err)...)
+	}
+	return &Error{Phase: phase, Path: path, Err: err}
+}
+
+// handleError passes err to [AppConfig.OnError].
+func (app *App) handleError(ctx *Context, err error) {
+	app.onError(ctx, err)
+}
+
+// ReportError reports an error of phase occurred in elem to [AppConfig.OnError].
+// It is used by widgets to report the errors that can't be returned,
+// such as native errors in [Element.SetWidget].
+func (ctx *Context) ReportError(phase Phase, elem Element, err error) {
+	ctx.app.handleError(ctx, &Error{Phase: phase, Path: widgetPath(elem), Err: err})
+}
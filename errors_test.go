@@ -0,0 +1,181 @@
+package goui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mkch/goui/native"
+	"github.com/mkch/goui/native/headless"
+	"github.com/mkch/goui/signal"
+)
+
+// failingPostBackend is a headless backend whose Post fails with postErr if it is not nil.
+type failingPostBackend struct {
+	*headless.Backend
+	postErr error
+}
+
+func (b *failingPostBackend) Post(f func()) error {
+	if b.postErr != nil {
+		return b.postErr
+	}
+	return b.Backend.Post(f)
+}
+
+// newErrorTestApp creates an app with a window showing root,
+// and collects the errors passed to OnError.
+func newErrorTestApp(t *testing.T, root Widget) (app *App, backend *failingPostBackend, errs *[]*Error) {
+	errs = new([]*Error)
+	backend = &failingPostBackend{Backend: headless.New()}
+	app = newApp(&AppConfig{
+		Debug:   &Debug{},
+		Backend: backend,
+		OnError: func(ctx *Context, err error) {
+			*errs = append(*errs, err.(*Error))
+		},
+	}, backend)
+	if err := app.CreateWindow(Window{ID: ValueID("window"), Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestOnError_Build(t *testing.T) {
+	createErr := errors.New("create error")
+	broken := &mockWidget{ID: ValueID("broken"), createError: createErr}
+	stateless := NewStatelessWidget(ValueID("stateless"), func(ctx *Context) Widget { return broken })
+	root := &layouterContainer{layouter: &countingLayouter{}, children: []Widget{stateless}}
+	app, _, errs := newErrorTestApp(t, root)
+
+	if err := app.buildWindows(); !errors.Is(err, createErr) {
+		t.Fatalf("expected %v, got %v", createErr, err)
+	}
+	if len(*errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(*errs))
+	}
+	e := (*errs)[0]
+	if e.Phase != PhaseBuild || len(e.Path) != 3 || e.Path[0] != root || e.Path[1] != stateless || e.Path[2] != broken {
+		t.Fatalf("unexpected error %v", e)
+	}
+	const want = `build error at *goui.layouterContainer/*goui.statelessWidget[stateless]/*goui.mockWidget[broken]: create error`
+	if e.Error() != want {
+		t.Fatalf("expected %q, got %q", want, e.Error())
+	}
+}
+
+func TestOnError_Reconcile(t *testing.T) {
+	createErr := errors.New("create error")
+	var update UpdateStateFunc
+	fail := false
+	stateful := NewStatefulWidget(ValueID("stateful"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			if fail {
+				return &mockWidget{ID: ValueID("broken"), createError: createErr}
+			}
+			return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		}}
+	})
+	root := &layouterContainer{layouter: &countingLayouter{}, children: []Widget{stateful}}
+	app, _, errs := newErrorTestApp(t, root)
+	if err := app.buildWindows(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := update(func() { fail = true }); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Flush(); !errors.Is(err, createErr) {
+		t.Fatalf("expected %v, got %v", createErr, err)
+	}
+	if len(*errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(*errs))
+	}
+	if e := (*errs)[0]; e.Phase != PhaseReconcile || len(e.Path) != 3 || e.Path[1] != stateful {
+		t.Fatalf("unexpected error %v", e)
+	}
+}
+
+func TestOnError_Layout(t *testing.T) {
+	layoutErr := errors.New("layout error")
+	app, backend, errs := newErrorTestApp(t, &mockWidget{element: &ElementBase{ElementLayouter: &errorLayouter{err: layoutErr}}})
+	app.buildWindows()
+	backend.Windows()[0].Resize(10, 10) // Does not panic.
+	if len(*errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(*errs))
+	}
+	for _, e := range *errs {
+		if e.Phase != PhaseLayout || !errors.Is(e, layoutErr) || len(e.Path) != 1 {
+			t.Fatalf("unexpected error %v", e)
+		}
+	}
+}
+
+func TestContext_ReportError(t *testing.T) {
+	app, _, errs := newErrorTestApp(t, &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}})
+	if err := app.buildWindows(); err != nil {
+		t.Fatal(err)
+	}
+	window := app.windows[ValueID("window")]
+	nativeErr := errors.New("native error")
+	(&Context{app: app, window: window}).ReportError(PhaseNative, window.Root, nativeErr)
+	if len(*errs) != 1 || (*errs)[0].Phase != PhaseNative || !errors.Is((*errs)[0], nativeErr) {
+		t.Fatalf("unexpected errors %v", *errs)
+	}
+}
+
+func TestOnError_Schedule(t *testing.T) {
+	count := signal.New(0)
+	stateless := NewStatelessWidget(ValueID("stateless"), func(ctx *Context) Widget {
+		count.Get()
+		return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	})
+	root := &layouterContainer{layouter: &countingLayouter{}, children: []Widget{stateless}}
+	app, backend, errs := newErrorTestApp(t, root)
+	if err := app.buildWindows(); err != nil {
+		t.Fatal(err)
+	}
+	backend.postErr = errors.New("post error")
+	count.Set(1) // Does not panic.
+	if len(*errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(*errs))
+	}
+	if e := (*errs)[0]; e.Phase != PhaseNative || !errors.Is(e, backend.postErr) || len(e.Path) != 2 || e.Path[1] != stateless {
+		t.Fatalf("unexpected error %v", e)
+	}
+}
+
+func TestOnError_NativeDestroy(t *testing.T) {
+	destroyErr := errors.New("destroy error")
+	var update UpdateStateFunc
+	removed := false
+	stateful := NewStatefulWidget(ValueID("stateful"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			children := []Widget{}
+			if !removed {
+				children = append(children, &mockWidget{ID: ValueID("native"), element: &NativeElement{
+					ElementBase: ElementBase{ElementLayouter: &mockLayouter{}},
+					DestroyFunc: func(native.Handle) error { return destroyErr },
+				}})
+			}
+			return &layouterContainer{layouter: &countingLayouter{}, children: children}
+		}}
+	})
+	app, _, errs := newErrorTestApp(t, stateful)
+	if err := app.buildWindows(); err != nil {
+		t.Fatal(err)
+	}
+	if err := update(func() { removed = true }); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(*errs))
+	}
+	if e := (*errs)[0]; e.Phase != PhaseNative || !errors.Is(e, destroyErr) || len(e.Path) != 3 {
+		t.Fatalf("unexpected error %v", e)
+	}
+}
@@ -0,0 +1,81 @@
+package goui
+
+import "errors"
+
+// ErrFragmentNotInContainer is returned when a [Fragment] is built other than as a child of a [Container].
+var ErrFragmentNotInContainer = errors.New("Fragment is not a child of a container")
+
+// Fragment is a widget that groups widgets without an element of its own.
+// When a Fragment is a child of a [Container], its widgets are flattened into the
+// children of the container, as if they were the children of the container themselves.
+// The flattened children are matched by their IDs among all the children of the container,
+// and are laid out by the layouter of the container. Fragments can be nested.
+//
+// A Fragment can also be built by a [StatelessWidget] or [StatefulWidget] in a Container,
+// so a widget can add more than one child to the container. The widgets of the Fragment
+// are children of the element of the stateless or stateful widget, which are matched by
+// their IDs among themselves, and are laid out by the layouter of the container.
+//
+// A Fragment can't be used other than as above, such as the root of a window, the
+// widget of an [ErrorBoundary], or the child of a [SingleChildContainer], where
+// [ErrFragmentNotInContainer] is returned.
+type Fragment struct {
+	Widgets []Widget
+}
+
+func (f *Fragment) WidgetID() ID {
+	return nil
+}
+
+func (f *Fragment) CreateElement(ctx *Context) (Element, error) {
+	return nil, ErrFragmentNotInContainer
+}
+
+// checkFragmentChild returns [ErrFragmentNotInContainer] if container is a [SingleChildContainer]
+// whose child is a Fragment.
+func checkFragmentChild(container Container) error {
+	if _, ok := container.(SingleChildContainer); !ok {
+		return nil
+	}
+	for i := range container.NumChildren() {
+		if _, isFragment := container.Child(i).(*Fragment); isFragment {
+			return ErrFragmentNotInContainer
+		}
+	}
+	return nil
+}
+
+// checkFragmentBuilt returns [ErrFragmentNotInContainer] if elem is the element of
+// a [SingleChildContainer] whose child builds a Fragment.
+func checkFragmentBuilt(elem Element) error {
+	if _, ok := elem.Widget().(SingleChildContainer); !ok {
+		return nil
+	}
+	for i := range elem.numChildren() {
+		if buildsFragment(elem.child(i)) {
+			return ErrFragmentNotInContainer
+		}
+	}
+	return nil
+}
+
+// childWidgets returns the children of container, with the widgets of Fragments flattened.
+func childWidgets(container Container) []Widget {
+	widgets := make([]Widget, 0, container.NumChildren())
+	for i := range container.NumChildren() {
+		widgets = appendFlattened(widgets, container.Child(i))
+	}
+	return widgets
+}
+
+// appendFlattened appends widget, or the flattened widgets if widget is a Fragment, to widgets.
+func appendFlattened(widgets []Widget, widget Widget) []Widget {
+	fragment, ok := widget.(*Fragment)
+	if !ok {
+		return append(widgets, widget)
+	}
+	for _, w := range fragment.Widgets {
+		widgets = appendFlattened(widgets, w)
+	}
+	return widgets
+}
@@ -0,0 +1,180 @@
+package goui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFragment(t *testing.T) {
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}})
+	leaf := func(id string) Widget {
+		return &mockWidget{ID: ValueID(id), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	}
+	a, b, c, d := leaf("a"), leaf("b"), leaf("c"), leaf("d")
+	root, _, err := buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+		a,
+		&Fragment{Widgets: []Widget{b, &Fragment{Widgets: []Widget{c}}}},
+		d,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.numChildren() != 4 || root.child(1).Widget() != b || root.child(2).Widget() != c || root.child(3).Widget() != d {
+		t.Fatalf("children of fragments are not flattened")
+	}
+	elemC := root.child(2)
+
+	// The children are matched by ID among the flattened children.
+	c2 := leaf("c")
+	if _, _, err = reconcileElementTree(ctx, root, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+		&Fragment{Widgets: []Widget{c2, a}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if root.numChildren() != 2 || root.child(0) != elemC || root.child(0).Widget() != c2 || root.child(1).Widget() != a {
+		t.Fatalf("children of fragments are not matched by ID")
+	}
+
+	// Duplicate IDs across fragments.
+	var dupErr *DuplicateIDError
+	if _, _, err = buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: []Widget{
+		leaf("a"), &Fragment{Widgets: []Widget{leaf("a")}},
+	}}); !errors.As(err, &dupErr) {
+		t.Fatalf("expected duplicate ID error, got %v", err)
+	}
+
+	// Not a child of a container.
+	if _, _, err = buildElementTree(ctx, &Fragment{Widgets: []Widget{leaf("a")}}); !errors.Is(err, ErrFragmentNotInContainer) {
+		t.Fatalf("expected %v, got %v", ErrFragmentNotInContainer, err)
+	}
+}
+
+func TestFragment_Built(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	leaf := func(id string) Widget {
+		return &mockWidget{ID: ValueID(id), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	}
+	ids := []string{"a", "b", "c"}
+	var update UpdateStateFunc
+	rows := NewStatefulWidget(ValueID("rows"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			widgets := make([]Widget, len(ids))
+			for i, id := range ids {
+				widgets[i] = leaf(id)
+			}
+			if len(widgets) == 1 {
+				return widgets[0]
+			}
+			return &Fragment{Widgets: widgets}
+		}}
+	})
+	container := &countingLayouter{}
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, &layouterContainer{layouter: container, children: []Widget{leaf("x"), rows}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	elem := ctx.window.Root.child(1)
+	children := func() (ids []ID) {
+		for layouter := range ctx.window.Layouter.Children() {
+			ids = append(ids, layouter.Element().Widget().WidgetID())
+		}
+		return
+	}
+	if want := []ID{ValueID("x"), ValueID("a"), ValueID("b"), ValueID("c")}; !slices.Equal(children(), want) {
+		t.Fatalf("expected layouter children %v, got %v", want, children())
+	}
+	elemB := elem.child(1)
+
+	rebuild := func(newIDs ...string) {
+		if err := update(func() { ids = newIDs }); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.app.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The widgets of the Fragment are matched by ID, and laid out by the container.
+	container.layouts = 0
+	rebuild("c", "b")
+	if elem.numChildren() != 2 || elem.child(1) != elemB {
+		t.Fatalf("children of the fragment are not matched by ID")
+	}
+	if want := []ID{ValueID("x"), ValueID("c"), ValueID("b")}; !slices.Equal(children(), want) {
+		t.Fatalf("expected layouter children %v, got %v", want, children())
+	}
+	if container.layouts != 1 {
+		t.Fatalf("expected the container to be laid out once, got %d", container.layouts)
+	}
+
+	// From a Fragment to a single widget, and back.
+	rebuild("b")
+	if elem.numChildren() != 1 || elem.child(0) != elemB {
+		t.Fatalf("the single widget is not matched by ID")
+	}
+	rebuild("a", "b")
+	if elem.numChildren() != 2 || elem.child(1) != elemB {
+		t.Fatalf("children of the fragment are not matched by ID")
+	}
+
+	// Not in a container.
+	if _, _, err = buildElementTree(ctx, rows); !errors.Is(err, ErrFragmentNotInContainer) {
+		t.Fatalf("expected %v, got %v", ErrFragmentNotInContainer, err)
+	}
+}
+
+// singleChildContainer is a [layouterContainer] with at most one child.
+type singleChildContainer struct {
+	layouterContainer
+}
+
+func (c *singleChildContainer) SingleChild() { /*Nop*/ }
+
+func TestFragment_SingleChild(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	leaf := func(id string) Widget {
+		return &mockWidget{ID: ValueID(id), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	}
+	single := func(child Widget) Widget {
+		return &singleChildContainer{layouterContainer{layouter: &countingLayouter{}, children: []Widget{child}}}
+	}
+
+	// The child is a Fragment.
+	if _, _, err := buildElementTree(ctx, single(&Fragment{Widgets: []Widget{leaf("a"), leaf("b")}})); !errors.Is(err, ErrFragmentNotInContainer) {
+		t.Fatalf("expected %v, got %v", ErrFragmentNotInContainer, err)
+	}
+
+	// The child builds a Fragment.
+	fragment := NewStatelessWidget(nil, func(ctx *Context) Widget {
+		return &Fragment{Widgets: []Widget{leaf("a"), leaf("b")}}
+	})
+	if _, _, err := buildElementTree(ctx, single(fragment)); !errors.Is(err, ErrFragmentNotInContainer) {
+		t.Fatalf("expected %v, got %v", ErrFragmentNotInContainer, err)
+	}
+
+	// The child builds a Fragment when rebuilt.
+	ids := []string{"a"}
+	var update UpdateStateFunc
+	rows := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			if len(ids) == 1 {
+				return leaf(ids[0])
+			}
+			return &Fragment{Widgets: []Widget{leaf(ids[0]), leaf(ids[1])}}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, single(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = update(func() { ids = []string{"a", "b"} }); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); !errors.Is(err, ErrFragmentNotInContainer) {
+		t.Fatalf("expected %v, got %v", ErrFragmentNotInContainer, err)
+	}
+}
@@ -0,0 +1,195 @@
+package goui
+
+import (
+	"slices"
+)
+
+// frame records the stateful and stateless elements waiting to be rebuilt in the next frame.
+type frame struct {
+	scheduled bool               // Whether a frame has been posted.
+	flushing  bool               // Whether the frame is being flushed.
+	dirty     []buildableElement // Elements marked dirty, in the order of marking.
+	// moved are the windows in which elements of GlobalIDs are moved away from
+	// their parents, which are laid out entirely.
+	moved []*window
+}
+
+// markNeedsBuild marks elem dirty and schedules a frame if not scheduled yet.
+func (app *App) markNeedsBuild(elem buildableElement) error {
+	c := elem.component()
+	if c.dirty {
+		return nil
+	}
+	c.dirty = true
+	app.frame.dirty = append(app.frame.dirty, elem)
+	if app.frame.flushing {
+		return nil
+	}
+	return app.scheduleFrame()
+}
+
+// scheduleFrame posts a frame, unless it is scheduled already.
+func (app *App) scheduleFrame() error {
+	if app.frame.scheduled {
+		return nil
+	}
+	app.frame.scheduled = true
+	if err := app.Post(app.runFrame); err != nil {
+		app.frame.scheduled = false
+		return err
+	}
+	return nil
+}
+
+// scheduleBuild calls [App.markNeedsBuild], and reports the error to [AppConfig.OnError],
+// for the callers that can't return errors.
+func (app *App) scheduleBuild(elem buildableElement) {
+	if err := app.markNeedsBuild(elem); err != nil {
+		elem.component().ctx.ReportError(PhaseNative, elem, err)
+	}
+}
+
+// runFrame is the posted function of a scheduled frame.
+func (app *App) runFrame() {
+	app.Flush() // The errors are handled in Flush.
+}
+
+// Flush rebuilds all the widgets whose states or inherited values are updated since
+// the last frame, and then lays out the affected windows in a single pass.
+// Elements are rebuilt from the root down, so an element rebuilt as part of its
+// dirty ancestor is not rebuilt again. Elements marked dirty during the rebuild,
+// such as the dependents of an updated [Inherited] widget, are rebuilt in the same frame.
+//
+// Flush is called automatically once per frame on the GUI goroutine.
+// Calling it explicitly applies the pending updates immediately, which is useful in tests.
+// The errors are passed to [AppConfig.OnError], and the first one is returned.
+func (app *App) Flush() (firstErr error) {
+	if err := app.checkGoroutine("flush"); err != nil {
+		return err
+	}
+	app.frame.scheduled = false
+	app.frame.flushing = true
+	defer func() { app.frame.flushing = false }()
+
+	// Relayout boundaries of the dirty layouters, grouped by window.
+	var windows []*window
+	relayout := make(map[*window][]Layouter)
+	app.beginReconcile()
+	for len(app.frame.dirty) > 0 {
+		dirty := app.frame.dirty
+		app.frame.dirty = nil
+
+		depths := make(map[buildableElement]int, len(dirty))
+		for _, elem := range dirty {
+			depths[elem] = elementDepth(elem)
+		}
+		slices.SortStableFunc(dirty, func(a, b buildableElement) int {
+			return depths[a] - depths[b]
+		})
+
+		for _, elem := range dirty {
+			c := elem.component()
+			if !c.dirty || c.destroyed || c.inactive {
+				continue // Rebuilt with an ancestor, destroyed or removed.
+			}
+			layouter, err := rebuildElementGuarded(elem)
+			if err != nil {
+				err = app.newError(PhaseReconcile, elem, err)
+				app.handleError(c.ctx, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			if layouter == nil {
+				continue
+			}
+			window := c.ctx.window
+			if _, ok := relayout[window]; !ok {
+				windows = append(windows, window)
+			}
+			relayout[window] = append(relayout[window], markNeedsLayout(layouter))
+		}
+	}
+	app.endReconcile()
+	for _, window := range app.frame.moved {
+		if window.Layouter == nil {
+			continue
+		}
+		if _, ok := relayout[window]; !ok {
+			windows = append(windows, window)
+		}
+		// The old parents are marked dirty up to the root, see [reclaimElement].
+		window.Layouter.layouterBase().needsLayout = true
+		relayout[window] = append(relayout[window], window.Layouter)
+	}
+	app.frame.moved = nil
+
+	for _, window := range windows {
+		ctx := &Context{app: app, window: window}
+		if err := relayoutWindow(ctx, relayout[window]); err != nil {
+			err = app.newError(PhaseLayout, window.Root, err)
+			app.handleError(ctx, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	// The elements marked while laying out, like an ErrorBoundary whose child failed,
+	// are rebuilt in the next frame.
+	if len(app.frame.dirty) > 0 {
+		if err := app.scheduleFrame(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return
+}
+
+// relayoutWindow lays out the dirty subtrees of a window in a single pass, which are rooted
+// at the given relayout boundaries, see [markNeedsLayout]. The boundaries are laid out from
+// the root down, each with its last constraints at its last position, and the ones laid out
+// along with an ancestor are skipped. The root of the window is laid out with the window size.
+func relayoutWindow(ctx *Context, boundaries []Layouter) error {
+	depths := make(map[Layouter]int, len(boundaries))
+	for _, boundary := range boundaries {
+		depths[boundary] = layouterDepth(boundary)
+	}
+	slices.SortStableFunc(boundaries, func(a, b Layouter) int {
+		return depths[a] - depths[b]
+	})
+	for _, boundary := range boundaries {
+		base := boundary.layouterBase()
+		if !base.needsLayout {
+			continue
+		}
+		if depths[boundary] == 0 {
+			if err := layoutWindow(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := boundary.Layout(ctx, base.constraints); err != nil {
+			return err
+		}
+		if err := boundary.PositionAt(base.pos.X, base.pos.Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// layouterDepth returns the number of ancestors of layouter.
+func layouterDepth(layouter Layouter) (depth int) {
+	for parent := layouter.Parent(); parent != nil; parent = parent.Parent() {
+		depth++
+	}
+	return
+}
+
+// elementDepth returns the number of ancestors of elem.
+func elementDepth(elem Element) (depth int) {
+	for parent := elem.parent(); parent != nil; parent = parent.parent() {
+		depth++
+	}
+	return
+}
@@ -0,0 +1,258 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+type countingLayouter struct {
+	LayouterBase
+	layouts int
+}
+
+func (l *countingLayouter) Layout(ctx *Context, constraints Constraints) (Size, error) {
+	l.layouts++
+	return constraints.MinSize(), nil
+}
+
+func (l *countingLayouter) PositionAt(x, y int) error {
+	return nil
+}
+
+// newHeadlessContext creates a mock context in debug mode with a headless window.
+func newHeadlessContext(t *testing.T) *Context {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestFlush_Coalesce(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	var outerBuilds, innerBuilds int
+	var updateOuter, updateInner UpdateStateFunc
+	inner := NewStatefulWidget(ValueID("inner"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		updateInner = updateState
+		return &WidgetState{Build: func() Widget {
+			innerBuilds++
+			return &mockWidget{ID: ValueID("leaf"), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		}}
+	})
+	rootLayouter := &countingLayouter{}
+	outer := NewStatefulWidget(ValueID("outer"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		updateOuter = updateState
+		return &WidgetState{Build: func() Widget {
+			outerBuilds++
+			return &layouterContainer{layouter: rootLayouter, children: []Widget{inner}}
+		}}
+	})
+
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, outer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outerBuilds, innerBuilds = 0, 0
+
+	for range 3 {
+		if err = updateInner(func() {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = updateOuter(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if outerBuilds != 0 || innerBuilds != 0 {
+		t.Fatalf("rebuilt before flush")
+	}
+	if !backend.RunPending() {
+		t.Fatalf("no frame is posted")
+	}
+	if outerBuilds != 1 || innerBuilds != 1 {
+		t.Fatalf("expected 1 rebuild each, got outer=%d inner=%d", outerBuilds, innerBuilds)
+	}
+	if rootLayouter.layouts != 1 {
+		t.Fatalf("expected 1 layout pass, got %d", rootLayouter.layouts)
+	}
+
+	// Nothing is pending.
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if outerBuilds != 1 || innerBuilds != 1 || rootLayouter.layouts != 1 {
+		t.Fatalf("unexpected rebuild or layout")
+	}
+}
+
+// layouterContainer is a container widget with a given layouter.
+type layouterContainer struct {
+	layouter Layouter
+	children []Widget
+}
+
+func (c *layouterContainer) WidgetID() ID {
+	return nil
+}
+
+func (c *layouterContainer) CreateElement(ctx *Context) (Element, error) {
+	return &ElementBase{ElementLayouter: c.layouter}, nil
+}
+
+func (c *layouterContainer) NumChildren() int {
+	return len(c.children)
+}
+
+func (c *layouterContainer) Child(n int) Widget {
+	return c.children[n]
+}
+
+func (c *layouterContainer) Exclusive(Container) { /*Nop*/ }
+
+// boxLayouter lays out its children with its own constraints, or the tight constraints
+// of its max size, and takes the max size.
+type boxLayouter struct {
+	LayouterBase
+	fixed   bool // Whether FixedSize returns true.
+	tight   bool // Whether the children are tightly constrained.
+	layouts int
+}
+
+func (l *boxLayouter) Layout(ctx *Context, constraints Constraints) (Size, error) {
+	l.layouts++
+	size := constraints.MaxSize()
+	childConstraints := constraints
+	if l.tight {
+		childConstraints = Constraints{MinWidth: size.Width, MinHeight: size.Height, MaxWidth: size.Width, MaxHeight: size.Height}
+	}
+	for child := range l.Children() {
+		if _, err := child.Layout(ctx, childConstraints); err != nil {
+			return Size{}, err
+		}
+	}
+	return size, nil
+}
+
+func (l *boxLayouter) PositionAt(x, y int) error {
+	for child := range l.Children() {
+		if err := child.PositionAt(x, y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *boxLayouter) FixedSize() bool {
+	return l.fixed
+}
+
+func TestFlush_RelayoutBoundary(t *testing.T) {
+	tests := []struct {
+		name                           string
+		root, middle                   *boxLayouter
+		rootRelayouts, middleRelayouts int
+	}{
+		{"not a boundary", &boxLayouter{}, &boxLayouter{}, 1, 1},
+		{"fixed size", &boxLayouter{}, &boxLayouter{fixed: true}, 0, 1},
+		// The rebuilt leaf is tightly constrained by middle.
+		{"tightly constrained", &boxLayouter{}, &boxLayouter{tight: true}, 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newHeadlessContext(t)
+			var update UpdateStateFunc
+			stateful := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+				update = updateState
+				return &WidgetState{Build: func() Widget {
+					return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+				}}
+			})
+			middle := &layouterContainer{layouter: test.middle, children: []Widget{stateful}}
+			root := &layouterContainer{layouter: test.root, children: []Widget{middle}}
+			var err error
+			if ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, root); err != nil {
+				t.Fatal(err)
+			}
+			if err = layoutWindow(ctx); err != nil {
+				t.Fatal(err)
+			}
+			test.root.layouts, test.middle.layouts = 0, 0
+
+			// Updated twice, and laid out once.
+			for range 2 {
+				if err = update(func() {}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err = ctx.app.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if test.root.layouts != test.rootRelayouts || test.middle.layouts != test.middleRelayouts {
+				t.Fatalf("expected %d root and %d middle layouts, got %d and %d",
+					test.rootRelayouts, test.middleRelayouts, test.root.layouts, test.middle.layouts)
+			}
+			if test.root.needsLayout || test.middle.needsLayout {
+				t.Fatalf("layouters are still dirty")
+			}
+		})
+	}
+}
+
+func TestFlush_LayoutCache(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	root, rebuilt, clean := &boxLayouter{}, &boxLayouter{}, &boxLayouter{}
+	var update UpdateStateFunc
+	stateful := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			return &layouterContainer{layouter: rebuilt}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx,
+		&layouterContainer{layouter: root, children: []Widget{stateful, &layouterContainer{layouter: clean}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = layoutWindow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stats := ctx.app.LayoutCacheStats(); stats != (LayoutCacheStats{Misses: 3}) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// Laid out again with the same constraints.
+	if err = layoutWindow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if root.layouts != 1 {
+		t.Fatalf("expected 1 root layout, got %d", root.layouts)
+	}
+
+	// The widget of rebuilt changes, and root has a dirty child.
+	if err = update(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if root.layouts != 2 || rebuilt.layouts != 2 || clean.layouts != 1 {
+		t.Fatalf("expected 2, 2 and 1 layouts, got %d, %d and %d", root.layouts, rebuilt.layouts, clean.layouts)
+	}
+	if stats := ctx.app.LayoutCacheStats(); stats != (LayoutCacheStats{Hits: 2, Misses: 5}) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// Not counted if debug mode is off.
+	if stats := newMockContext(&AppConfig{}).app.LayoutCacheStats(); stats != (LayoutCacheStats{}) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
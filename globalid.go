@@ -0,0 +1,222 @@
+package goui
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ErrGlobalIDOfOtherApp is returned when a widget with a [GlobalID] registered in an app
+// is built in another app.
+var ErrGlobalIDOfOtherApp = errors.New("GlobalID is used in another app")
+
+// GlobalID is an [ID] unique in the whole app, rather than among the siblings.
+// The element of a widget with a GlobalID is registered in the app, so when the widget
+// is moved to another parent in the same window, reconciliation moves the element tree
+// along with it instead of recreating it, and the states and native controls are preserved.
+// The move must be done in a single frame, that is, the old parent stops building
+// the widget and the new parent starts building it in the same [App.Flush].
+// A GlobalID can be used in one app only, otherwise [ErrGlobalIDOfOtherApp] is returned,
+// and by one widget at a time, otherwise a [DuplicateIDError] is returned.
+// Create a GlobalID with [NewGlobalID].
+type GlobalID struct {
+	name string
+	app  *App // The app the element of the ID is registered in.
+}
+
+// NewGlobalID creates a new GlobalID. The name is used for debugging only.
+func NewGlobalID(name string) *GlobalID {
+	return &GlobalID{name: name}
+}
+
+func (*GlobalID) privateImplementsID() {}
+
+func (id *GlobalID) String() string {
+	return id.name
+}
+
+// globalElement is an element registered for a [GlobalID].
+type globalElement struct {
+	elem   Element
+	window *window
+}
+
+// inactiveElements holds the element trees containing elements of GlobalIDs,
+// which are removed during reconciliation. They are destroyed when the outermost
+// reconciliation ends, unless the elements of the GlobalIDs are reclaimed.
+type inactiveElements struct {
+	depth int // The depth of the nested reconciliations in progress.
+	trees []Element
+	// claimed are the elements built or updated for GlobalIDs in the outermost
+	// reconciliation, see [App.claimGlobalID].
+	claimed map[*GlobalID]Element
+}
+
+// beginReconcile is called before a reconciliation, see [inactiveElements].
+func (app *App) beginReconcile() {
+	if app.inactive.depth == 0 {
+		app.inactive.claimed = nil
+	}
+	app.inactive.depth++
+}
+
+// endReconcile is called after a reconciliation, see [inactiveElements].
+func (app *App) endReconcile() {
+	app.inactive.depth--
+	if app.inactive.depth > 0 {
+		return
+	}
+	trees := app.inactive.trees
+	app.inactive.trees = nil
+	for _, elem := range trees {
+		app.destroyElementTree(elem)
+	}
+}
+
+// registerGlobalID registers elem if widget has a GlobalID, which is claimed
+// with [App.claimGlobalID] before elem is created.
+func registerGlobalID(ctx *Context, elem Element, widget Widget) {
+	id, ok := widget.WidgetID().(*GlobalID)
+	if !ok {
+		return
+	}
+	id.app = ctx.app
+	if ctx.app.globals == nil {
+		ctx.app.globals = make(map[*GlobalID]globalElement)
+	}
+	ctx.app.globals[id] = globalElement{elem: elem, window: ctx.window}
+	ctx.app.claimGlobalID(widget, elem) // Checked before elem is created.
+}
+
+// claimGlobalID records that widget, if it has a GlobalID, is built into elem in the
+// current reconciliation. Elem is nil if the element is not created or reclaimed yet,
+// in which case nothing is recorded.
+// [ErrGlobalIDOfOtherApp] is returned if the GlobalID is registered in another app, and
+// a [DuplicateIDError] if the GlobalID is claimed for another element in the reconciliation,
+// that is, the GlobalID is used by more than one widget.
+func (app *App) claimGlobalID(widget Widget, elem Element) error {
+	id, ok := widget.WidgetID().(*GlobalID)
+	if !ok {
+		return nil
+	}
+	if id.app != nil && id.app != app {
+		return fmt.Errorf("%w: %v", ErrGlobalIDOfOtherApp, id)
+	}
+	if claimed, ok := app.inactive.claimed[id]; ok && claimed != elem {
+		return &DuplicateIDError{ID: id, Index1: -1, Index2: -1}
+	}
+	if elem == nil {
+		return nil
+	}
+	if app.inactive.claimed == nil {
+		app.inactive.claimed = make(map[*GlobalID]Element)
+	}
+	app.inactive.claimed[id] = elem
+	return nil
+}
+
+// destroyElementTree unregisters the elements of GlobalIDs in the element tree
+// rooted at elem, forgets their recreations recorded by [App.checkRecreated],
+// and destroys the tree.
+func (app *App) destroyElementTree(elem Element) {
+	var unregister func(elem Element)
+	unregister = func(elem Element) {
+		if id, ok := elem.Widget().WidgetID().(*GlobalID); ok && app.globals[id].elem == elem {
+			delete(app.globals, id)
+		}
+		delete(app.recreated, elem)
+		for i := range elem.numChildren() {
+			unregister(elem.child(i))
+		}
+	}
+	unregister(elem)
+	elem.destroy()
+}
+
+// reclaimElement detaches the element registered for the GlobalID of widget from
+// its old parent, and returns it to be reinserted under the element built with ctx.
+// The returned element is activated, and its contexts are updated for the new parent.
+// Nil is returned if widget has no GlobalID, or the registered element can't be moved,
+// because it is of another type, in another window, the root of a window, or an
+// ancestor of the new parent.
+func reclaimElement(ctx *Context, widget Widget) Element {
+	id, ok := widget.WidgetID().(*GlobalID)
+	if !ok {
+		return nil
+	}
+	app := ctx.app
+	global, ok := app.globals[id]
+	if !ok || global.window != ctx.window || reflect.TypeOf(global.elem.Widget()) != reflect.TypeOf(widget) {
+		return nil
+	}
+	elem := global.elem
+	for c := ctx; c != nil; c = c.parent {
+		if c.element == elem {
+			return nil
+		}
+	}
+
+	root := elem
+	for root.parent() != nil {
+		root = root.parent()
+	}
+	i := slices.Index(app.inactive.trees, root)
+	if root == elem {
+		if i < 0 {
+			return nil // The root of a window.
+		}
+		app.inactive.trees = slices.Delete(app.inactive.trees, i, i+1)
+	} else {
+		parent := elem.parent()
+		detachElement(elem)
+		if i < 0 { // Moved from a live parent, which may not be rebuilt.
+			for ; parent != nil; parent = parent.parent() {
+				if layouter := parent.Layouter(); layouter != nil {
+					layouter.layouterBase().needsLayout = true // The cached size is out of date.
+				}
+			}
+			app.frame.moved = append(app.frame.moved, ctx.window)
+		}
+	}
+	if i >= 0 {
+		activateElementTree(elem)
+	}
+	reparentElementTree(ctx, elem)
+	return elem
+}
+
+// detachElement removes elem from the children of its parent without destroying it.
+func detachElement(elem Element) {
+	parent := elem.parent()
+	children := make([]Element, 0, parent.numChildren()-1)
+	for i := range parent.numChildren() {
+		if child := parent.child(i); child != elem {
+			children = append(children, child)
+		}
+	}
+	element_UpdateChildren(parent, children)
+	elem.setParent(nil)
+}
+
+// reparentElementTree updates the element tree rooted at elem, which is moved under
+// the element built with ctx. The contexts of the nearest stateless and stateful elements
+// are linked to ctx, and the elements depending on [Inherited] widgets are rebuilt,
+// because the ancestors change.
+func reparentElementTree(ctx *Context, elem Element) {
+	if b, ok := elem.(buildableElement); ok {
+		c := b.component()
+		if ctx != nil {
+			c.ctx.parent = ctx
+		}
+		if len(c.dependencies) > 0 {
+			c.clearDependencies()
+			c.dependenciesChanged = true
+			c.ctx.app.scheduleBuild(b)
+		}
+		ctx = nil // The contexts below are linked to c.ctx.
+	}
+	for i := range elem.numChildren() {
+		reparentElementTree(ctx, elem.child(i))
+	}
+}
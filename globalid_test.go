@@ -0,0 +1,163 @@
+package goui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+func TestGlobalID_Move(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	global := NewGlobalID("counter")
+	var inits, deactivates, activates, destroys int
+	counter := NewStatefulWidget(global, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		inits++
+		return &WidgetState{
+			Build:       func() Widget { return &nativeWidget{text: "counter"} },
+			Deactivate:  func() { deactivates++ },
+			Activate:    func() { activates++ },
+			DestroyData: func() { destroys++ },
+		}
+	})
+	side := "left"
+	slot := func(name string) Widget {
+		return NewStatelessWidget(ValueID(name), func(ctx *Context) Widget {
+			children := []Widget{&nativeWidget{ID: ValueID(name), text: name}}
+			if side == name {
+				children = append(children, counter)
+			}
+			return &layouterContainer{layouter: &countingLayouter{}, children: children}
+		})
+	}
+	var update UpdateStateFunc
+	root := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{slot("left"), slot("right")}}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elem := ctx.app.globals[global].elem.(*statefulElement)
+	label := elem.child(0).(*NativeElement).Handle.(*headless.Object)
+	slots := ctx.window.Root.child(0)
+	order := func() (texts []string) {
+		for _, obj := range ctx.window.Handle.(*headless.Object).Children {
+			texts = append(texts, obj.Text)
+		}
+		return
+	}
+	move := func(to string) {
+		if err := update(func() { side = to }); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.app.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Moved forward: removed from the left first, and then reinserted to the right.
+	move("right")
+	if ctx.app.globals[global].elem != elem || slots.child(1).child(0).child(1) != elem {
+		t.Fatalf("element is not moved")
+	}
+	if elem.ctx.parent != slots.child(1).(*statelessElement).ctx {
+		t.Fatalf("context is not linked to the new parent")
+	}
+	if inits != 1 || deactivates != 1 || activates != 1 || destroys != 0 {
+		t.Fatalf("unexpected lifecycle: %d inits, %d deactivates, %d activates, %d destroys", inits, deactivates, activates, destroys)
+	}
+	if label.Destroyed || elem.child(0).(*NativeElement).Handle != label {
+		t.Fatalf("native control is not preserved")
+	}
+	if want := []string{"left", "right", "counter"}; !slices.Equal(order(), want) {
+		t.Fatalf("expected native order %q, got %q", want, order())
+	}
+
+	// Moved backward: taken from the right before the right is rebuilt.
+	move("left")
+	if slots.child(0).child(0).child(1) != elem || slots.child(1).child(0).numChildren() != 1 {
+		t.Fatalf("element is not moved")
+	}
+	if inits != 1 || destroys != 0 {
+		t.Fatalf("unexpected lifecycle: %d inits, %d destroys", inits, destroys)
+	}
+	if want := []string{"left", "counter", "right"}; !slices.Equal(order(), want) {
+		t.Fatalf("expected native order %q, got %q", want, order())
+	}
+
+	// Removed.
+	move("")
+	if destroys != 1 || !label.Destroyed {
+		t.Fatalf("element is not destroyed")
+	}
+	if _, ok := ctx.app.globals[global]; ok {
+		t.Fatalf("GlobalID is not unregistered")
+	}
+}
+
+func TestGlobalID_Duplicate(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	global := NewGlobalID("counter")
+	inits := 0
+	counter := func() Widget {
+		return NewStatefulWidget(global, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+			inits++
+			return &WidgetState{Build: func() Widget { return &nativeWidget{text: "counter"} }}
+		})
+	}
+	sides := []string{"left"}
+	slot := func(name string) Widget {
+		return NewStatelessWidget(ValueID(name), func(ctx *Context) Widget {
+			children := []Widget{&nativeWidget{ID: ValueID(name), text: name}}
+			if slices.Contains(sides, name) {
+				children = append(children, counter())
+			}
+			return &layouterContainer{layouter: &countingLayouter{}, children: children}
+		})
+	}
+	var update UpdateStateFunc
+	root := NewStatefulWidget(nil, func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			return &layouterContainer{layouter: &countingLayouter{}, children: []Widget{slot("left"), slot("right")}}
+		}}
+	})
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elem := ctx.app.globals[global].elem
+	slots := ctx.window.Root.child(0)
+
+	// Built on both sides in the same frame.
+	if err = update(func() { sides = []string{"left", "right"} }); err != nil {
+		t.Fatal(err)
+	}
+	var dupErr *DuplicateIDError
+	if err = ctx.app.Flush(); !errors.As(err, &dupErr) || dupErr.ID != global {
+		t.Fatalf("expected duplicate GlobalID error, got %v", err)
+	}
+	if slots.child(0).child(0).child(1) != elem || ctx.app.globals[global].elem != elem || inits != 1 {
+		t.Fatalf("element is moved or recreated by the duplicate")
+	}
+
+	// Duplicate in the first build.
+	global = NewGlobalID("counter2")
+	ctx = newHeadlessContext(t)
+	if _, _, err = buildElementTree(ctx, root); !errors.As(err, &dupErr) || dupErr.ID != global {
+		t.Fatalf("expected duplicate GlobalID error, got %v", err)
+	}
+
+	// Used in another app.
+	sides = []string{"left"}
+	if _, _, err = buildElementTree(newHeadlessContext(t), root); !errors.Is(err, ErrGlobalIDOfOtherApp) {
+		t.Fatalf("expected ErrGlobalIDOfOtherApp, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package goui
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/mkch/gg/errortrace"
+)
+
+// goroutineID returns the ID of the calling goroutine.
+// The ID is parsed from the first line of the stack trace, "goroutine 123 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	s := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(s, ' '); i >= 0 {
+		s = s[:i]
+	}
+	id, _ := strconv.ParseUint(string(s), 10, 64)
+	return id
+}
+
+// onGUIGoroutine returns whether the calling goroutine is the GUI goroutine of app.
+func (app *App) onGUIGoroutine() bool {
+	return goroutineID() == app.guiGoroutine
+}
+
+// WrongGoroutineError is returned in debug mode when the element tree is
+// accessed off the GUI goroutine.
+type WrongGoroutineError struct {
+	Op string // The operation performed, e.g. "reconcile".
+}
+
+func (e *WrongGoroutineError) Error() string {
+	return fmt.Sprintf("%v off the GUI goroutine, use App.Post or UpdateStateFunc instead", e.Op)
+}
+
+// checkGoroutine returns a [WrongGoroutineError] if debug mode is on and
+// the calling goroutine is not the GUI goroutine.
+func (app *App) checkGoroutine(op string) error {
+	if app.debug == nil || app.onGUIGoroutine() {
+		return nil
+	}
+	return errortrace.WithStack(&WrongGoroutineError{Op: op})
+}
@@ -0,0 +1,96 @@
+package goui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+func TestUpdateState_OffGUIGoroutine(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	var updateState UpdateStateFunc
+	var builds int
+	widget := NewStatefulWidget(nil, func(ctx *Context, update UpdateStateFunc) *WidgetState {
+		updateState = update
+		return &WidgetState{Build: func() Widget {
+			builds++
+			return &mockWidget{element: &ElementBase{}}
+		}}
+	})
+	if ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, widget); err != nil {
+		t.Fatal(err)
+	}
+
+	var updatedOnGUI bool
+	result := make(chan (<-chan error))
+	go func() {
+		result <- updateState.Async(func() { updatedOnGUI = ctx.app.onGUIGoroutine() })
+	}()
+	done := <-result
+	select {
+	case <-done:
+		t.Fatalf("update is done before the GUI goroutine runs it")
+	default:
+	}
+	backend.RunPending()
+	if err = <-done; err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if !updatedOnGUI {
+		t.Fatalf("update is not called on the GUI goroutine")
+	}
+	if builds != 2 {
+		t.Fatalf("expected the widget to be rebuilt, builds = %d", builds)
+	}
+
+	// Mutating the element tree directly off the GUI goroutine is reported in debug mode.
+	errs := make(chan error)
+	go func() {
+		_, _, err := reconcileElementTree(ctx, ctx.window.Root, widget)
+		errs <- err
+	}()
+	var wrongGoroutine *WrongGoroutineError
+	if err = <-errs; !errors.As(err, &wrongGoroutine) || wrongGoroutine.Op != "reconcile" {
+		t.Fatalf("expected WrongGoroutineError, got %v", err)
+	}
+}
+
+func TestUpdateState_AsyncScheduleError(t *testing.T) {
+	var updateState UpdateStateFunc
+	widget := NewStatefulWidget(nil, func(ctx *Context, update UpdateStateFunc) *WidgetState {
+		updateState = update
+		return &WidgetState{Build: func() Widget {
+			return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		}}
+	})
+	app, backend, errs := newErrorTestApp(t, widget)
+	if err := app.buildWindows(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := make(chan (<-chan error))
+	go func() {
+		result <- updateState.Async(func() {})
+	}()
+	done := <-result
+	// The update is posted, but the frame can't be.
+	backend.postErr = errors.New("post error")
+	backend.RunPending()
+	if err := <-done; err != backend.postErr {
+		t.Fatalf("expected %v, got %v", backend.postErr, err)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("unexpected second value %v", err)
+	default:
+	}
+	if len(*errs) != 0 {
+		t.Fatalf("the error is reported to OnError: %v", *errs)
+	}
+}
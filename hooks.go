@@ -0,0 +1,243 @@
+package goui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Hooks are functions like [UseState] that keep data in the element of a [StatefulWidget]
+// across builds. They are keyed by the order they are called in a build, so they must be
+// called in the Build of the state, unconditionally and in the same order in every build.
+// The ctx passed to a hook must be the one passed to [StatefulWidget.CreateState],
+// or the one passed to [HookWidgetFunc].
+
+// hooks is the hook list of a stateful element.
+type hooks struct {
+	list     []any
+	index    int  // The index of the next hook in list.
+	building bool // Whether the state is being built.
+	built    bool // Whether the state has been built once, after which the number of hooks is fixed.
+}
+
+// begin is called before the state is built.
+func (h *hooks) begin() {
+	h.index = 0
+	h.building = true
+}
+
+// end is called after the state is built.
+func (h *hooks) end() {
+	h.building = false
+	if h.built && h.index != len(h.list) {
+		panic(fmt.Sprintf("%d hooks called in Build, %d in the previous build; hooks must be called in the same order", h.index, len(h.list)))
+	}
+	h.built = true
+}
+
+// runEffects runs the effects whose dependencies are changed in the last build.
+func (h *hooks) runEffects() {
+	for _, hook := range h.list {
+		if effect, ok := hook.(*effectHook); ok && effect.pending {
+			effect.pending = false
+			effect.cleanup()
+			effect.clean = effect.effect()
+		}
+	}
+}
+
+// dispose runs the cleanups of all the effects.
+func (h *hooks) dispose() {
+	for _, hook := range h.list {
+		if effect, ok := hook.(*effectHook); ok {
+			effect.cleanup()
+		}
+	}
+}
+
+// useHook returns the hook of type H at the current index of the stateful element
+// of ctx, and advances the index. If no such hook exists in the first build,
+// a new one is created with newHook.
+func useHook[H any](ctx *Context, name string, newHook func(elem *statefulElement) H) (hook H, created bool) {
+	elem, ok := ctx.element.(*statefulElement)
+	if !ok || !elem.hooks.building {
+		panic(fmt.Sprintf("%v must be called in the Build of a StatefulWidget with the context of its state", name))
+	}
+	h := &elem.hooks
+	defer func() { h.index++ }()
+	if h.index < len(h.list) {
+		if hook, ok = h.list[h.index].(H); !ok {
+			panic(fmt.Sprintf("hook #%d is %v, was %T in the previous build; hooks must be called in the same order", h.index, name, h.list[h.index]))
+		}
+		return
+	}
+	if h.built {
+		panic(fmt.Sprintf("%v is called as hook #%d, which did not exist in the previous build; hooks must be called in the same order", name, h.index))
+	}
+	hook = newHook(elem)
+	h.list = append(h.list, hook)
+	return hook, true
+}
+
+type stateHook[T any] struct {
+	value T
+	set   func(T) error
+}
+
+// UseState returns the current value of a state, which is initial in the first build,
+// and a function to update the value. Setting the value schedules the widget to be rebuilt,
+// and like [UpdateStateFunc], can be done from any goroutine.
+func UseState[T any](ctx *Context, initial T) (value T, set func(T) error) {
+	hook, _ := useHook(ctx, "UseState", func(elem *statefulElement) *stateHook[T] {
+		hook := &stateHook[T]{value: initial}
+		hook.set = func(value T) error {
+			return updateWidgetState(func() { hook.value = value }, elem)
+		}
+		return hook
+	})
+	return hook.value, hook.set
+}
+
+type effectHook struct {
+	deps    []any
+	effect  func() func()
+	clean   func()
+	pending bool // Whether effect is to be run after the build.
+}
+
+func (h *effectHook) cleanup() {
+	if h.clean != nil {
+		h.clean()
+		h.clean = nil
+	}
+}
+
+// UseEffect runs effect after the widget is built, if deps are changed since the last
+// build. The deps are compared element by element with ==. A nil deps is always
+// considered changed, while an empty deps never changes after the first build.
+//
+// Effect can return a cleanup function, or nil. The cleanup is called before the effect
+// runs again, and when the element is destroyed.
+func UseEffect(ctx *Context, effect func() (cleanup func()), deps []any) {
+	hook, created := useHook(ctx, "UseEffect", func(*statefulElement) *effectHook {
+		return &effectHook{}
+	})
+	if created || !depsEqual(hook.deps, deps) {
+		hook.effect = effect
+		hook.deps = slices.Clone(deps)
+		hook.pending = true
+	}
+}
+
+type memoHook[T any] struct {
+	deps  []any
+	value T
+}
+
+// UseMemo returns the value computed by compute, which is called only in the first build
+// and when deps are changed. See [UseEffect] for the comparison of deps.
+func UseMemo[T any](ctx *Context, compute func() T, deps []any) T {
+	hook, created := useHook(ctx, "UseMemo", func(*statefulElement) *memoHook[T] {
+		return &memoHook[T]{}
+	})
+	if created || !depsEqual(hook.deps, deps) {
+		hook.value = compute()
+		hook.deps = slices.Clone(deps)
+	}
+	return hook.value
+}
+
+// UseRef returns a pointer to a value, which is initial in the first build.
+// The same pointer is returned in every build, and modifying the value pointed
+// to does not cause a rebuild.
+func UseRef[T any](ctx *Context, initial T) *T {
+	ref, _ := useHook(ctx, "UseRef", func(*statefulElement) *T {
+		return &initial
+	})
+	return ref
+}
+
+// depsEqual reports whether the deps of two builds are the same.
+func depsEqual(old, new []any) bool {
+	if old == nil || new == nil || len(old) != len(new) {
+		return false
+	}
+	for i := range old {
+		a, b := old[i], new[i]
+		if a == nil || b == nil {
+			if a != b {
+				return false
+			}
+			continue
+		}
+		if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.ValueOf(a).Comparable() || a != b {
+			return false
+		}
+	}
+	return true
+}
+
+// HookWidgetFunc is a [StatefulWidget] built by calling the function,
+// in which hooks such as [UseState] can be used with ctx.
+type HookWidgetFunc func(ctx *Context) Widget
+
+func (f HookWidgetFunc) WidgetID() ID {
+	return nil
+}
+
+func (f HookWidgetFunc) CreateElement(ctx *Context) (Element, error) {
+	return createStatefulElement(ctx), nil
+}
+
+func (f HookWidgetFunc) CreateState(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+	return newHookState(ctx, f)
+}
+
+func (f HookWidgetFunc) Exclusive(StatefulWidget) { /*Nop*/ }
+
+// newHookState creates the state of a hook widget, which builds with the
+// build function of the latest widget.
+func newHookState(ctx *Context, build func(ctx *Context) Widget) *WidgetState {
+	return &WidgetState{
+		Build: func() Widget {
+			return build(ctx)
+		},
+		DidUpdateWidget: func(old, new Widget) {
+			switch w := new.(type) {
+			case HookWidgetFunc:
+				build = w
+			case *hookWidget:
+				build = w.build
+			}
+		},
+	}
+}
+
+// hookWidget is an implementation of hook widget with ID.
+type hookWidget struct {
+	id    ID
+	build func(ctx *Context) Widget
+}
+
+func (w *hookWidget) WidgetID() ID {
+	return w.id
+}
+
+func (w *hookWidget) CreateElement(ctx *Context) (Element, error) {
+	return createStatefulElement(ctx), nil
+}
+
+func (w *hookWidget) CreateState(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+	return newHookState(ctx, w.build)
+}
+
+func (w *hookWidget) Exclusive(StatefulWidget) { /*Nop*/ }
+
+// NewHookWidget creates a new StatefulWidget with the given ID, built by calling build,
+// in which hooks such as [UseState] can be used with ctx.
+func NewHookWidget(id ID, build func(ctx *Context) Widget) StatefulWidget {
+	return &hookWidget{
+		id:    id,
+		build: build,
+	}
+}
@@ -0,0 +1,156 @@
+package goui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+func TestHooks(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	var log []string
+	var setCount func(int) error
+	var ref *int
+	var builds int
+	var counts []int
+	dep := "a"
+	widget := func() Widget {
+		return HookWidgetFunc(func(ctx *Context) Widget {
+			builds++
+			count, set := UseState(ctx, 10)
+			setCount = set
+			ref = UseRef(ctx, 0)
+			*ref++
+			memo := UseMemo(ctx, func() string { log = append(log, "memo "+dep); return dep }, []any{dep})
+			UseEffect(ctx, func() func() {
+				log = append(log, "effect "+memo)
+				return func() { log = append(log, "cleanup "+memo) }
+			}, []any{memo})
+			UseEffect(ctx, func() func() {
+				log = append(log, "once")
+				return nil
+			}, []any{})
+			counts = append(counts, count)
+			return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		})
+	}
+	expect := func(events ...string) {
+		t.Helper()
+		if !slices.Equal(log, events) {
+			t.Fatalf("expected %v, got %v", events, log)
+		}
+		log = nil
+	}
+
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, widget())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := ctx.window.Root
+	expect("memo a", "effect a", "once")
+
+	if err = setCount(11); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if builds != 2 || *ref != 2 {
+		t.Fatalf("expected 2 builds and ref 2, got %d and %d", builds, *ref)
+	}
+	expect()
+
+	dep = "b"
+	if _, _, err = reconcileElementTree(ctx, root, widget()); err != nil {
+		t.Fatal(err)
+	}
+	// Count is not reset by the new widget.
+	if err = setCount(12); err != nil {
+		t.Fatal(err)
+	}
+	expect("memo b", "cleanup a", "effect b")
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expect()
+
+	if _, _, err = reconcileElementTree(ctx, root, &mockWidget{element: &ElementBase{}}); err != nil {
+		t.Fatal(err)
+	}
+	expect("cleanup b")
+	if want := []int{10, 11, 11, 12}; !slices.Equal(counts, want) {
+		t.Fatalf("expected counts %v, got %v", want, counts)
+	}
+}
+
+func TestHooks_Order(t *testing.T) {
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: headless.New()})
+	first := true
+	widget := HookWidgetFunc(func(ctx *Context) Widget {
+		if first {
+			UseRef(ctx, 0)
+		} else {
+			UseState(ctx, 0)
+		}
+		return &mockWidget{element: &ElementBase{}}
+	})
+	root, _, err := buildElementTree(ctx, widget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first = false
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on hook order change")
+		}
+	}()
+	reconcileElementTree(ctx, root, widget)
+}
+
+func TestHooks_BuildPanic(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	var hookCtx *Context
+	boundary := &ErrorBoundary{
+		Widget: HookWidgetFunc(func(ctx *Context) Widget {
+			hookCtx = ctx
+			UseRef(ctx, 0)
+			panic("build panic")
+		}),
+		Fallback: func(ctx *Context, err error, retry func()) Widget {
+			return &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		},
+	}
+	if _, _, err := buildElementTree(ctx, boundary); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on hook called outside Build")
+		}
+	}()
+	UseRef(hookCtx, 0)
+}
+
+func TestDepsEqual(t *testing.T) {
+	for _, test := range []struct {
+		old, new []any
+		equal    bool
+	}{
+		{nil, nil, false},
+		{[]any{}, []any{}, true},
+		{[]any{1, "a", nil}, []any{1, "a", nil}, true},
+		{[]any{1}, []any{int64(1)}, false},
+		{[]any{1}, []any{1, 2}, false},
+		{[]any{[]int{1}}, []any{[]int{1}}, false},
+	} {
+		if got := depsEqual(test.old, test.new); got != test.equal {
+			t.Errorf("depsEqual(%v, %v) = %v, want %v", test.old, test.new, got, test.equal)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package goui
 
+import "fmt"
+
 // ID uniquely identifies a GUI element in its parent container.
 type ID interface {
 	privateImplementsID() // unexported to prevent external implementations
@@ -12,6 +14,10 @@ type valueID[T comparable] struct {
 
 func (valueID[T]) privateImplementsID() {}
 
+func (id valueID[T]) String() string {
+	return fmt.Sprint(id.value)
+}
+
 // ValueID creates an ID from a comparable value.
 func ValueID[T comparable](value T) ID {
 	return valueID[T]{value: value}
@@ -0,0 +1,119 @@
+package goui
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Inherited is a widget that provides Value to the widgets built below it.
+// A descendant reads the value with [DependOn], and is rebuilt automatically
+// when the value changes.
+// Inherited has no layouter of its own, Widget is laid out as if it were not wrapped.
+type Inherited[T any] struct {
+	ID     ID
+	Value  T
+	Widget Widget
+	// Changed reports whether the value has changed from old to new, so the
+	// dependents need to be rebuilt.
+	// If Changed is nil, the values are compared with ==, or always
+	// considered changed if they are not comparable.
+	Changed func(old, new T) bool
+}
+
+func (w *Inherited[T]) WidgetID() ID {
+	return w.ID
+}
+
+func (w *Inherited[T]) CreateElement(ctx *Context) (Element, error) {
+	return &inheritedElement{}, nil
+}
+
+func (w *Inherited[T]) Build(ctx *Context) Widget {
+	return w.Widget
+}
+
+func (w *Inherited[T]) Exclusive(StatelessWidget) { /*Nop*/ }
+
+func (w *Inherited[T]) changed(old Widget) bool {
+	oldValue := old.(*Inherited[T]).Value
+	if w.Changed != nil {
+		return w.Changed(oldValue, w.Value)
+	}
+	a, b := any(oldValue), any(w.Value)
+	if a == nil || b == nil {
+		return a != b
+	}
+	// The dynamic values are checked, because T can be an interface type holding
+	// a value which is not comparable.
+	return !reflect.ValueOf(a).Comparable() || !reflect.ValueOf(b).Comparable() || a != b
+}
+
+// inheritedWidget is implemented by [Inherited].
+type inheritedWidget interface {
+	StatelessWidget
+	// changed reports whether the value has changed from the one of old,
+	// which is of the same type.
+	changed(old Widget) bool
+}
+
+type inheritedElement struct {
+	componentElement
+	dependents []buildableElement
+}
+
+func (e *inheritedElement) build() Widget {
+	return e.Widget().(StatelessWidget).Build(e.ctx)
+}
+
+// SetWidget sets the widget of e, and marks the dependents dirty if
+// the value has changed.
+func (e *inheritedElement) SetWidget(ctx *Context, widget Widget) {
+	old := e.Widget()
+	e.componentElement.SetWidget(ctx, widget)
+	if old == nil || !widget.(inheritedWidget).changed(old) {
+		return
+	}
+	for _, dependent := range e.dependents {
+		dependent.component().dependenciesChanged = true
+		ctx.app.scheduleBuild(dependent)
+	}
+}
+
+func (e *inheritedElement) addDependent(dependent buildableElement) {
+	if !slices.Contains(e.dependents, dependent) {
+		e.dependents = append(e.dependents, dependent)
+		dependent.component().addDependency(e)
+	}
+}
+
+func (e *inheritedElement) removeDependent(dependent *componentElement) {
+	e.dependents = slices.DeleteFunc(e.dependents, func(elem buildableElement) bool {
+		return elem.component() == dependent
+	})
+}
+
+// DependOn returns the value of the nearest ancestor [Inherited] widget of type
+// Inherited[T], and ok is true. If no such ancestor exists, the zero value and false is returned.
+//
+// The stateless or stateful element ctx is associated with, which is the one being built,
+// or the nearest one above the native widget ctx is passed to, is registered as a dependent
+// of the Inherited widget, and is rebuilt when the value changes.
+func DependOn[T any](ctx *Context) (value T, ok bool) {
+	// The contexts are walked instead of the elements, because the parents
+	// of the elements are not set until their children are built.
+	for c := ctx; c != nil; c = c.parent {
+		inherited, isInherited := c.element.(*inheritedElement)
+		if !isInherited {
+			continue
+		}
+		widget, match := inherited.Widget().(*Inherited[T])
+		if !match {
+			continue
+		}
+		if c != ctx {
+			inherited.addDependent(ctx.element)
+		}
+		return widget.Value, true
+	}
+	return
+}
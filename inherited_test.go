@@ -0,0 +1,150 @@
+package goui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+func TestDependOn(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	var seen []int
+	dependent := NewStatelessWidget(ValueID("dependent"), func(ctx *Context) Widget {
+		value, ok := DependOn[int](ctx)
+		if !ok {
+			t.Fatalf("no Inherited[int] found")
+		}
+		seen = append(seen, value)
+		if _, ok := DependOn[string](ctx); ok {
+			t.Fatalf("unexpected Inherited[string] found")
+		}
+		return &mockWidget{ID: ValueID("leaf"), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	})
+	inherited := func(value int) Widget {
+		return &Inherited[int]{
+			Value:  value,
+			Widget: &layouterContainer{layouter: &countingLayouter{}, children: []Widget{dependent}},
+		}
+	}
+
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, inherited(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := ctx.window.Root.(*inheritedElement)
+	if len(root.dependents) != 1 {
+		t.Fatalf("expected 1 dependent, got %d", len(root.dependents))
+	}
+
+	// Same value, the dependent is not marked dirty.
+	root.SetWidget(ctx, inherited(1))
+	if len(ctx.app.frame.dirty) != 0 {
+		t.Fatalf("dependent marked dirty with unchanged value")
+	}
+
+	// The value changes, only the dependent is rebuilt.
+	root.SetWidget(ctx, inherited(2))
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2}; len(seen) != len(want) || seen[0] != want[0] || seen[1] != want[1] {
+		t.Fatalf("expected values %v, got %v", want, seen)
+	}
+
+	// Destroyed dependents are unregistered.
+	_, _, err = reconcileElementTree(ctx, root, &Inherited[int]{Value: 3, Widget: &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root.dependents) != 0 {
+		t.Fatalf("expected no dependent, got %d", len(root.dependents))
+	}
+}
+
+func TestDependOn_Cleared(t *testing.T) {
+	ctx := newHeadlessContext(t)
+	depend := true
+	builds := 0
+	var update UpdateStateFunc
+	dependent := NewStatefulWidget(ValueID("dependent"), func(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+		update = updateState
+		return &WidgetState{Build: func() Widget {
+			builds++
+			if depend {
+				DependOn[int](ctx)
+			}
+			return &mockWidget{ID: ValueID("leaf"), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+		}}
+	})
+	inherited := func(value int) Widget {
+		return &Inherited[int]{
+			Value:  value,
+			Widget: &layouterContainer{layouter: &countingLayouter{}, children: []Widget{dependent}},
+		}
+	}
+	var err error
+	ctx.window.Root, ctx.window.Layouter, err = buildElementTree(ctx, inherited(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := ctx.window.Root.(*inheritedElement)
+
+	// The dependent stops calling DependOn.
+	if err = update(func() { depend = false }); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(root.dependents) != 0 {
+		t.Fatalf("expected no dependent, got %d", len(root.dependents))
+	}
+	builds = 0
+	root.SetWidget(ctx, inherited(2))
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if builds != 0 {
+		t.Fatalf("the element no longer depending on the value is rebuilt")
+	}
+}
+
+// stringerFunc is a [fmt.Stringer] which is not comparable.
+type stringerFunc func() string
+
+func (f stringerFunc) String() string {
+	return f()
+}
+
+func TestInherited_Changed(t *testing.T) {
+	if (&Inherited[[]int]{Value: nil}).changed(&Inherited[[]int]{Value: nil}) != true {
+		t.Fatal("values of incomparable type are always changed")
+	}
+	if (&Inherited[string]{Value: "a"}).changed(&Inherited[string]{Value: "a"}) {
+		t.Fatal("equal values are not changed")
+	}
+	// The dynamic values of interface types may not be comparable.
+	if !(&Inherited[any]{Value: []int{1}}).changed(&Inherited[any]{Value: []int{1}}) {
+		t.Fatal("incomparable values in interface are always changed")
+	}
+	if !(&Inherited[fmt.Stringer]{Value: stringerFunc(nil)}).changed(&Inherited[fmt.Stringer]{Value: stringerFunc(nil)}) {
+		t.Fatal("incomparable values in interface are always changed")
+	}
+	if (&Inherited[any]{Value: 1}).changed(&Inherited[any]{Value: 1}) {
+		t.Fatal("equal values in interface are not changed")
+	}
+	if (&Inherited[any]{}).changed(&Inherited[any]{}) || !(&Inherited[any]{Value: 1}).changed(&Inherited[any]{}) {
+		t.Fatal("nil values are compared")
+	}
+	w := &Inherited[string]{Value: "a", Changed: func(old, new string) bool { return old == new }}
+	if !w.changed(&Inherited[string]{Value: "a"}) {
+		t.Fatal("Changed is not used")
+	}
+}
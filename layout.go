@@ -51,6 +51,11 @@ func (c *Constraints) TightHeight() bool {
 	return c.MinHeight == c.MaxHeight && c.MinHeight != Infinity
 }
 
+// tight returns true if the constraints allow only one size.
+func (c *Constraints) tight() bool {
+	return c.TightWidth() && c.TightHeight()
+}
+
 // UnboundWidth returns true if no constraint is imposed on width.
 func (c *Constraints) UnboundWidth() bool {
 	return c.MaxWidth == Infinity
@@ -139,9 +144,25 @@ type Layouter interface {
 	Layout(ctx *Context, constraints Constraints) (Size, error)
 	// PositionAt puts the element at the given position.
 	PositionAt(x, y int) error
-	// Replayer returns a function that can replay the last layout operations,
-	// or nil if replay is not supported (e.g., when the layout depends on children).
-	Replayer() func(*Context) error
+	// MinIntrinsicWidth returns the smallest width the element can be laid out in
+	// without failing to show its content, given the height, which can be [Infinity].
+	MinIntrinsicWidth(height int) (int, error)
+	// MaxIntrinsicWidth returns the smallest width beyond which increasing the width
+	// doesn't decrease the preferred height, given the height, which can be [Infinity].
+	MaxIntrinsicWidth(height int) (int, error)
+	// MinIntrinsicHeight returns the smallest height the element can be laid out in
+	// without failing to show its content, given the width, which can be [Infinity].
+	MinIntrinsicHeight(width int) (int, error)
+	// MaxIntrinsicHeight returns the smallest height beyond which increasing the height
+	// doesn't decrease the preferred width, given the width, which can be [Infinity].
+	MaxIntrinsicHeight(width int) (int, error)
+	// Baseline returns the distance from the top of the element to the baseline of its
+	// first line of text, as of the last layout. Ok is false if the element has no baseline.
+	Baseline() (baseline int, ok bool, err error)
+	// FixedSize reports whether the size computed by Layout depends only on the constraints
+	// and the widget, not on the children. Such a layouter is a relayout boundary,
+	// see [markNeedsLayout].
+	FixedSize() bool
 	// Children returns an iterator of child layouters.
 	Children() iter.Seq[Layouter]
 	// Parent returns the parent layouter, or nil.
@@ -152,13 +173,29 @@ type Layouter interface {
 	// setElement is a helper function to set the creator of this layouter.
 	// The implementation should just set the element field or some equivalent.
 	setElement(element Element)
+	// layouterBase returns the embedded LayouterBase.
+	layouterBase() *LayouterBase
 }
 
 // LayouterBase is a helper struct for implementing Layouter.
 // Embedding LayouterBase in a struct and implementing
 // Layout and PositionAt methods implements the Layouter interface.
+//
+// LayouterBase caches the size of the last layout. Layout is not called again with the
+// same constraints, unless the widget of the element changes or a child is marked dirty,
+// so Layout must depend only on the constraints, the widget and the children.
 type LayouterBase struct {
 	element Element
+	// The last layout, recorded by [trackingLayouter].
+	laidOut     bool        // Whether the layouter has been laid out.
+	needsLayout bool        // Whether the layouter is marked dirty, see [markNeedsLayout].
+	constraints Constraints // The last constraints.
+	size        Size        // The last size.
+	pos         Point       // The last position.
+}
+
+func (l *LayouterBase) layouterBase() *LayouterBase {
+	return l
 }
 
 func (l *LayouterBase) Element() Element {
@@ -172,29 +209,159 @@ func (l *LayouterBase) setElement(element Element) {
 func (l *LayouterBase) Children() iter.Seq[Layouter] {
 	return func(yield func(Layouter) bool) {
 		for i := 0; i < l.element.numChildren(); i++ {
-			childLayouter := layouterTree(l.element.child(i))
-			if childLayouter == nil {
-				continue
-			}
-			if !yield(childLayouter) {
+			if !yieldLayouters(l.element.child(i), yield) {
 				return
 			}
 		}
 	}
 }
 
+// yieldLayouters yields the layouter of element, or the nearest layouters below it
+// if element has no layouter, which are more than one if a stateless or stateful
+// element builds a [Fragment]. It returns false if yield returns false.
+func yieldLayouters(element Element, yield func(Layouter) bool) bool {
+	if layouter := element.Layouter(); layouter != nil {
+		return yield(layouter)
+	}
+	if _, isContainer := element.Widget().(Container); isContainer {
+		panic("container without a layouter")
+	}
+	for i := range element.numChildren() {
+		if !yieldLayouters(element.child(i), yield) {
+			return false
+		}
+	}
+	return true
+}
+
 func (l *LayouterBase) Parent() (parent Layouter) {
-	for element := l.element.parent(); element != nil; element = element.parent() {
-		parent = element.Layouter()
-		if parent != nil {
-			return
+	return ancestorLayouter(l.element)
+}
+
+// ancestorLayouter returns the layouter of the nearest ancestor of element with a layouter,
+// or nil if not found.
+func ancestorLayouter(element Element) Layouter {
+	for element = element.parent(); element != nil; element = element.parent() {
+		if layouter := element.Layouter(); layouter != nil {
+			return layouter
 		}
 	}
 	return nil
 }
 
-func (l *LayouterBase) Replayer() func(*Context) error {
-	return nil
+func (l *LayouterBase) FixedSize() bool {
+	return false
+}
+
+// The intrinsic dimensions of LayouterBase are the max of the ones of the children,
+// or 0 if there is no child, which suits the layouters sizing themselves to their only child.
+
+func (l *LayouterBase) MinIntrinsicWidth(height int) (int, error) {
+	return l.maxChildIntrinsic(func(child Layouter) (int, error) { return child.MinIntrinsicWidth(height) })
+}
+
+func (l *LayouterBase) MaxIntrinsicWidth(height int) (int, error) {
+	return l.maxChildIntrinsic(func(child Layouter) (int, error) { return child.MaxIntrinsicWidth(height) })
+}
+
+func (l *LayouterBase) MinIntrinsicHeight(width int) (int, error) {
+	return l.maxChildIntrinsic(func(child Layouter) (int, error) { return child.MinIntrinsicHeight(width) })
+}
+
+func (l *LayouterBase) MaxIntrinsicHeight(width int) (int, error) {
+	return l.maxChildIntrinsic(func(child Layouter) (int, error) { return child.MaxIntrinsicHeight(width) })
+}
+
+// Baseline of LayouterBase returns the baseline of the first child with a baseline,
+// which suits the layouters putting their children at their own positions.
+func (l *LayouterBase) Baseline() (baseline int, ok bool, err error) {
+	for child := range l.Children() {
+		if baseline, ok, err = child.Baseline(); err != nil || ok {
+			return
+		}
+	}
+	return
+}
+
+// maxChildIntrinsic returns the max of the intrinsic dimensions of the children returned by f.
+func (l *LayouterBase) maxChildIntrinsic(f func(child Layouter) (int, error)) (result int, err error) {
+	for child := range l.Children() {
+		var value int
+		if value, err = f(child); err != nil {
+			return
+		}
+		result = max(result, value)
+	}
+	return
+}
+
+// LayoutCacheStats are the counters of the layout cache, see [LayouterBase].
+type LayoutCacheStats struct {
+	Hits   int // The number of layouts returning the cached size.
+	Misses int // The number of layouts calling Layout of the layouters.
+}
+
+// trackingLayouter is a [Layouter] wrapper that records the last layout in the [LayouterBase],
+// so a dirty subtree can be laid out again alone, see [relayoutWindow], and the size of
+// a clean layouter is returned from the cache.
+type trackingLayouter struct {
+	Layouter
+	stats *LayoutCacheStats // Nil if debug mode is off.
+}
+
+func (l *trackingLayouter) Layout(ctx *Context, constraints Constraints) (size Size, err error) {
+	base := l.layouterBase()
+	if base.laidOut && !base.needsLayout && base.constraints == constraints {
+		if l.stats != nil {
+			l.stats.Hits++
+		}
+		return base.size, nil
+	}
+	if l.stats != nil {
+		l.stats.Misses++
+	}
+	base.laidOut = false // Not cached if failed.
+	size, err = l.Layouter.Layout(ctx, constraints)
+	if err != nil {
+		return
+	}
+	base.laidOut = true
+	base.needsLayout = false
+	base.constraints = constraints
+	base.size = size
+	return
+}
+
+func (l *trackingLayouter) PositionAt(x, y int) (err error) {
+	err = l.Layouter.PositionAt(x, y)
+	if err != nil {
+		return
+	}
+	l.layouterBase().pos = Point{X: x, Y: y}
+	return
+}
+
+// markNeedsLayout marks the layouter of a rebuilt subtree and its ancestors dirty up to
+// the nearest relayout boundary, and returns the boundary, which is the root of the
+// subtree to lay out again. The size of the rebuilt layouter may change unless it is tightly
+// constrained, so may the size of each ancestor unless it is a relayout boundary, that is,
+// tightly constrained or [Layouter.FixedSize]. The root layouter is returned if no boundary is found.
+func markNeedsLayout(rebuilt Layouter) Layouter {
+	base := rebuilt.layouterBase()
+	base.needsLayout = true
+	if base.laidOut && base.constraints.tight() {
+		return rebuilt
+	}
+	layouter := rebuilt
+	for parent := layouter.Parent(); parent != nil; parent = layouter.Parent() {
+		layouter = parent
+		base = layouter.layouterBase()
+		base.needsLayout = true
+		if base.laidOut && (base.constraints.tight() || layouter.FixedSize()) {
+			break
+		}
+	}
+	return layouter
 }
 
 // debugLayouterVer records a debug layouter and its highlight version.
@@ -206,6 +373,7 @@ type debugLayouterVer struct {
 // debugLayouter is a [Layouter] wrapper that records debugging information.
 type debugLayouter struct {
 	Layouter
+	Outline              bool                // Whether to draw layout outline. If false, only size and position are recorded.
 	Size                 Size                // Last computed size
 	Pos                  Point               // Last computed position
 	Highlight            bool                // Whether to highlight the outline of this layouter
@@ -214,6 +382,15 @@ type debugLayouter struct {
 }
 
 func (l *debugLayouter) Layout(ctx *Context, constraints Constraints) (size Size, err error) {
+	if !l.Outline {
+		size, err = l.Layouter.Layout(ctx, constraints)
+		if err != nil {
+			return
+		}
+		l.Size = size // Record size
+		return
+	}
+
 	l.Highlight = true // Mark to highlight
 	l.HighlightVer++
 
@@ -230,7 +407,7 @@ func (l *debugLayouter) Layout(ctx *Context, constraints Constraints) (size Size
 				return // do not show highlight if layout fails
 			}
 			// Show highlight after laying out(include children) is done
-			native.InvalidWindow(ctx.window.Handle)
+			ctx.app.backend.InvalidWindow(ctx.window.Handle)
 			// Schedule canceling all highlights in the batch after a delay
 			const delay = 100 * time.Millisecond
 			batch := *l.CancelHighlightBatch
@@ -249,7 +426,7 @@ func (l *debugLayouter) Layout(ctx *Context, constraints Constraints) (size Size
 					}
 					// Request a redraw to remove the highlights
 					if cancelled {
-						native.InvalidWindow(ctx.window.Handle)
+						ctx.app.backend.InvalidWindow(ctx.window.Handle)
 					}
 				})
 			})
@@ -283,7 +460,7 @@ func allLayouterDebugOutlines(root Layouter) iter.Seq[native.DebugRect] {
 			current := stack[len(stack)-1]
 			stack = stack[:len(stack)-1]
 
-			if debugLayouter, ok := current.(*debugLayouter); ok {
+			if debugLayouter, ok := current.(*debugLayouter); ok && debugLayouter.Outline {
 				if !yield(native.DebugRect{
 					Left:      debugLayouter.Pos.X,
 					Top:       debugLayouter.Pos.Y,
@@ -317,3 +494,19 @@ func layouterTree(element Element) (layouter Layouter) {
 	}
 	return layouterTree(element.child(0))
 }
+
+// buildsFragment reports whether element or a descendant above the nearest layouter
+// is a stateless or stateful element building a [Fragment], so there may be more than
+// one nearest layouters, and [layouterTree] returns the first one.
+func buildsFragment(element Element) bool {
+	for element.Layouter() == nil {
+		if b, ok := element.(buildableElement); ok && b.component().fragment {
+			return true
+		}
+		if element.numChildren() == 0 {
+			return false
+		}
+		element = element.child(0)
+	}
+	return false
+}
@@ -13,12 +13,59 @@ func link_BuildElementTree(ctx *Context, widget Widget) (Element, Layouter, erro
 	return buildElementTree(ctx, widget)
 }
 
-//go:linkname link_NewMockContext github.com/mkch/goui/widgets/widgetstest.NewContext
-func link_NewMockContext() *Context {
-	return newMockContext(&AppConfig{Debug: &Debug{}})
+//go:linkname link_NewMockContext github.com/mkch/goui/widgets/widgetstest.newContext
+func link_NewMockContext(config *AppConfig) *Context {
+	return newMockContext(config)
 }
 
 //go:linkname link_Context_debug github.com/mkch/goui/internal/debug.debug
 func link_Context_debug(ctx *Context) *tricks.Debug {
 	return ctx.app.debug
 }
+
+//go:linkname link_NewTestApp github.com/mkch/goui/widgets/widgetstest.newTestApp
+func link_NewTestApp(config *AppConfig) *App {
+	app := NewApp(config)
+	app.recordLayout = true
+	return app
+}
+
+//go:linkname link_App_buildWindows github.com/mkch/goui/widgets/widgetstest.buildWindows
+func link_App_buildWindows(app *App) error {
+	return app.buildWindows()
+}
+
+//go:linkname link_App_rootElement github.com/mkch/goui/widgets/widgetstest.rootElement
+func link_App_rootElement(app *App, windowID ID) Element {
+	if window := app.windows[windowID]; window != nil {
+		return window.Root
+	}
+	return nil
+}
+
+//go:linkname link_Element_children github.com/mkch/goui/widgets/widgetstest.elementChildren
+func link_Element_children(elem Element) []Element {
+	children := make([]Element, elem.numChildren())
+	for i := range children {
+		children[i] = elem.child(i)
+	}
+	return children
+}
+
+//go:linkname link_Element_bounds github.com/mkch/goui/widgets/widgetstest.elementBounds
+func link_Element_bounds(elem Element) (bounds Rect, ok bool) {
+	layouter := layouterTree(elem)
+	if layouter == nil {
+		return
+	}
+	debugLayouter, ok := layouter.(*debugLayouter)
+	if !ok {
+		return
+	}
+	return Rect{
+		Left:   debugLayouter.Pos.X,
+		Top:    debugLayouter.Pos.Y,
+		Right:  debugLayouter.Pos.X + debugLayouter.Size.Width,
+		Bottom: debugLayouter.Pos.Y + debugLayouter.Size.Height,
+	}, true
+}
@@ -0,0 +1,82 @@
+// Package native defines the interface between goui and the underlying
+// platform GUI toolkit.
+package native
+
+import "iter"
+
+// Handle represents a platform-specific GUI object.
+type Handle any
+
+type MessageBoxIcon int
+
+const (
+	MessageBoxNone MessageBoxIcon = iota
+	MessageBoxIconInfo
+	MessageBoxIconWarning
+	MessageBoxIconError
+)
+
+// DebugRect is a rectangle drawn over a window to outline a layout area in debug mode.
+type DebugRect struct {
+	Left, Top, Right, Bottom int
+	Highlight                bool
+}
+
+// Backend is a native GUI implementation.
+// All methods except Post must be called on the GUI goroutine,
+// that is the goroutine calling Run.
+type Backend interface {
+	// Post posts a function to be executed on the GUI goroutine.
+	Post(f func()) error
+	// Run runs the message loop until Quit is called, and returns the exit code.
+	Run() int
+	// Quit makes Run return with exitCode.
+	Quit(exitCode int)
+
+	// CreateWindow creates a native window with the specified configuration.
+	CreateWindow(title string, width, height int) (Handle, error)
+	// InvalidWindow requests the window to be repainted.
+	InvalidWindow(handle Handle) error
+	// DestroyWindow destroys a window or a control.
+	DestroyWindow(handle Handle) error
+	SetWindowOnSizeChangedListener(handle Handle, onSizeChanged func(width, height int))
+	SetWindowOnCloseListener(handle Handle, onClose func())
+	// WindowClientRect returns the client area of the window.
+	WindowClientRect(handle Handle) (x, y, width, height int, err error)
+
+	CreateButton(parent Handle, title string) (Handle, error)
+	SetButtonOnClickListener(handle Handle, onClick func())
+	SetButtonLabel(handle Handle, label string)
+	// GetButtonMinimumSize returns the minimum size of the button to show label.
+	GetButtonMinimumSize(handle Handle, label string) (width, height int, err error)
+
+	CreateLabel(parent Handle, title string) (Handle, error)
+	SetLabelText(handle Handle, text string) error
+
+	CreateTextField(parent Handle, initialValue string, password bool) (Handle, error)
+	GetTextFieldText(handle Handle) (string, error)
+	SetTextFieldText(handle Handle, text string) error
+
+	// SetWidgetDimensions sets the position and size of a control relative to its window.
+	SetWidgetDimensions(handle Handle, x, y, width, height int) error
+	// SetWidgetSize sets the size of a control without moving it.
+	SetWidgetSize(handle Handle, width, height int) error
+	// SetWidgetZOrder moves a control right after the control after in the
+	// z-order and tab order of its window. If after is nil, the control is moved to the first.
+	SetWidgetZOrder(handle, after Handle) error
+	// GetTextDrawingSize returns the size required to draw the specified text
+	// in the given control.
+	// If multiline is true, the line ending characters are considered as line breaks.
+	GetTextDrawingSize(control Handle, text string, multiline bool) (width, height int, err error)
+	// GetTextMetrics returns the metrics of the font of the given control. Ascent is the
+	// distance from the top of a line of text to its baseline, and lineHeight is the height of a line.
+	GetTextMetrics(control Handle) (ascent, lineHeight int, err error)
+	// GetTextTop returns the distance from the top of the given control, which is height high,
+	// to the top of the first line of its text.
+	GetTextTop(control Handle, height int) (top int, err error)
+
+	// MessageBox shows a modal message box. Parent can be nil.
+	MessageBox(parent Handle, title, message string, icon MessageBoxIcon)
+	// EnableDrawDebugRect makes the window draw rects on top of its content when painting.
+	EnableDrawDebugRect(winHandle Handle, rects func() iter.Seq[DebugRect]) error
+}
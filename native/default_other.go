@@ -0,0 +1,9 @@
+//go:build !windows
+
+package native
+
+// NewDefaultBackend returns a new instance of the default [Backend] of the platform,
+// or nil if the platform has no default backend.
+func NewDefaultBackend() Backend {
+	return nil
+}
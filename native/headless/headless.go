@@ -0,0 +1,514 @@
+// Package headless provides a [native.Backend] that creates windows and controls
+// in memory. No OS specific resources are allocated, and every call to the backend
+// is recorded, so it can be used to test goui apps without a display.
+package headless
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mkch/goui/native"
+)
+
+// Kind is the kind of an [Object].
+type Kind int
+
+const (
+	Window Kind = iota
+	Button
+	Label
+	TextField
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Window:
+		return "window"
+	case Button:
+		return "button"
+	case Label:
+		return "label"
+	case TextField:
+		return "textfield"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Object is an in-memory window or control.
+// Object is the [native.Handle] returned by [Backend].
+type Object struct {
+	Kind     Kind
+	Serial   int       // Creation order of the object in its backend, starting from 1.
+	Parent   *Object   // The window of a control. Nil for windows.
+	Children []*Object // The controls of a window in z-order and tab order.
+	Text     string    // Title of window, label of button or text of label and text field.
+	Password bool      // Whether the text field is a password field.
+	// Bounds of a control relative to its window, or client size of a window.
+	X, Y, Width, Height int
+	Destroyed           bool
+
+	onClick       func()
+	onSizeChanged func(width, height int)
+	onClose       func()
+	debugRects    func() iter.Seq[native.DebugRect]
+}
+
+func (obj *Object) String() string {
+	if obj == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v#%d", obj.Kind, obj.Serial)
+}
+
+// Click simulates clicking on a button.
+func (obj *Object) Click() {
+	if obj.onClick != nil {
+		obj.onClick()
+	}
+}
+
+// Resize simulates the user resizing a window to the given client size.
+func (obj *Object) Resize(width, height int) {
+	obj.Width, obj.Height = width, height
+	if obj.onSizeChanged != nil {
+		obj.onSizeChanged(width, height)
+	}
+}
+
+// Close simulates the user closing a window.
+func (obj *Object) Close() {
+	if obj.onClose != nil {
+		obj.onClose()
+	}
+}
+
+// DebugRects returns the debug rects drawn on a window, or nil if
+// drawing debug rects is not enabled.
+func (obj *Object) DebugRects() []native.DebugRect {
+	if obj.debugRects == nil {
+		return nil
+	}
+	return slices.Collect(obj.debugRects())
+}
+
+// Find returns the first non-destroyed descendant of obj(depth first) that f returns true for,
+// or nil if not found.
+func (obj *Object) Find(f func(*Object) bool) *Object {
+	for _, child := range obj.Children {
+		if f(child) {
+			return child
+		}
+		if found := child.Find(f); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Op is a recorded call to [Backend].
+type Op struct {
+	Name   string  // Method name, e.g. "CreateButton".
+	Object *Object // The object created or operated on. Nil if none.
+	Args   []any   // Arguments other than the object.
+}
+
+func (op Op) String() string {
+	var buf strings.Builder
+	buf.WriteString(op.Name)
+	buf.WriteByte('(')
+	if op.Object != nil {
+		buf.WriteString(op.Object.String())
+	}
+	for i, arg := range op.Args {
+		if i > 0 || op.Object != nil {
+			buf.WriteString(", ")
+		}
+		if s, ok := arg.(string); ok {
+			fmt.Fprintf(&buf, "%q", s)
+		} else {
+			fmt.Fprint(&buf, arg)
+		}
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// Metrics of the default text measurement. Every rune is CharWidth wide and every line is LineHeight high,
+// with the baseline Ascent below the top of the line.
+const (
+	CharWidth  = 8
+	LineHeight = 16
+	Ascent     = 12
+	// ButtonEdge is the width of button edges on each side.
+	ButtonEdge = 2
+)
+
+// ErrDestroyed is returned when operating on a destroyed object.
+var ErrDestroyed = errors.New("object is destroyed")
+
+// Backend is an in-memory [native.Backend].
+// Create a Backend with [New].
+//
+// Like a native GUI, Backend is not safe for concurrent use: [Backend.Post] and
+// [Backend.Quit] can be called from any goroutine, but the other methods, and the
+// fields and objects of the backend, must be used on the GUI goroutine only, which
+// runs [Backend.Run] or [Backend.RunPending].
+type Backend struct {
+	// MeasureText returns the size required to draw text.
+	// If multiline is true, the line ending characters are considered as line breaks.
+	// If MeasureText is nil, text is measured with [CharWidth] and [LineHeight].
+	MeasureText func(text string, multiline bool) (width, height int)
+	// TextAscent is the distance from the top of a line of text to its baseline.
+	// If TextAscent is 0, [Ascent] is used.
+	TextAscent int
+	// TextTop returns the distance from the top of control, which is height high,
+	// to the top of its text. If TextTop is nil, the text is vertically centered
+	// in buttons, and drawn at the top of the other controls.
+	TextTop func(control *Object, height int) int
+
+	mu       sync.Mutex // Guards posted, quit and exitCode.
+	posted   []func()
+	wake     chan struct{}
+	quit     bool
+	exitCode int
+
+	serial  int
+	windows []*Object
+	ops     []Op
+}
+
+// New creates a new headless backend.
+func New() *Backend {
+	return &Backend{wake: make(chan struct{}, 1)}
+}
+
+func (b *Backend) record(name string, obj *Object, args ...any) {
+	b.ops = append(b.ops, Op{Name: name, Object: obj, Args: args})
+}
+
+// Ops returns the recorded operations.
+func (b *Backend) Ops() []Op {
+	return slices.Clone(b.ops)
+}
+
+// ResetOps clears the recorded operations.
+func (b *Backend) ResetOps() {
+	b.ops = nil
+}
+
+// Windows returns the windows that are not destroyed, in creation order.
+func (b *Backend) Windows() []*Object {
+	return slices.Clone(b.windows)
+}
+
+func (b *Backend) Post(f func()) error {
+	b.mu.Lock()
+	b.posted = append(b.posted, f)
+	b.mu.Unlock()
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// RunPending runs the functions posted so far, and those posted by them,
+// on the calling goroutine until there are no more.
+// It returns whether any function was run.
+func (b *Backend) RunPending() (ran bool) {
+	for {
+		b.mu.Lock()
+		posted := b.posted
+		b.posted = nil
+		b.mu.Unlock()
+		if len(posted) == 0 {
+			return
+		}
+		for _, f := range posted {
+			f()
+		}
+		ran = true
+	}
+}
+
+func (b *Backend) Run() int {
+	for {
+		b.RunPending()
+		b.mu.Lock()
+		quit, exitCode := b.quit, b.exitCode
+		b.mu.Unlock()
+		if quit {
+			return exitCode
+		}
+		<-b.wake
+	}
+}
+
+func (b *Backend) Quit(exitCode int) {
+	b.mu.Lock()
+	b.quit = true
+	b.exitCode = exitCode
+	b.mu.Unlock()
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *Backend) newObject(kind Kind, parent *Object, text string) *Object {
+	b.serial++
+	obj := &Object{Kind: kind, Serial: b.serial, Parent: parent, Text: text}
+	if parent != nil {
+		parent.Children = append(parent.Children, obj)
+	}
+	return obj
+}
+
+// object converts handle to a live object.
+func object(handle native.Handle) (*Object, error) {
+	obj := handle.(*Object)
+	if obj.Destroyed {
+		return nil, fmt.Errorf("%v: %w", obj, ErrDestroyed)
+	}
+	return obj, nil
+}
+
+func (b *Backend) CreateWindow(title string, width, height int) (native.Handle, error) {
+	win := b.newObject(Window, nil, title)
+	win.Width, win.Height = width, height
+	b.windows = append(b.windows, win)
+	b.record("CreateWindow", win, title, width, height)
+	return win, nil
+}
+
+func (b *Backend) InvalidWindow(handle native.Handle) error {
+	_, err := object(handle)
+	b.record("InvalidWindow", handle.(*Object))
+	return err
+}
+
+func (b *Backend) DestroyWindow(handle native.Handle) error {
+	obj, err := object(handle)
+	b.record("DestroyWindow", handle.(*Object))
+	if err != nil {
+		return err
+	}
+	b.destroy(obj)
+	return nil
+}
+
+func (b *Backend) destroy(obj *Object) {
+	for _, child := range obj.Children {
+		child.Destroyed = true
+	}
+	obj.Children = nil
+	obj.Destroyed = true
+	if obj.Parent != nil {
+		obj.Parent.Children = slices.DeleteFunc(obj.Parent.Children, func(o *Object) bool { return o == obj })
+	} else {
+		b.windows = slices.DeleteFunc(b.windows, func(o *Object) bool { return o == obj })
+	}
+}
+
+func (b *Backend) SetWindowOnSizeChangedListener(handle native.Handle, onSizeChanged func(width, height int)) {
+	b.record("SetWindowOnSizeChangedListener", handle.(*Object))
+	handle.(*Object).onSizeChanged = onSizeChanged
+}
+
+func (b *Backend) SetWindowOnCloseListener(handle native.Handle, onClose func()) {
+	b.record("SetWindowOnCloseListener", handle.(*Object))
+	handle.(*Object).onClose = onClose
+}
+
+func (b *Backend) WindowClientRect(handle native.Handle) (x, y, width, height int, err error) {
+	win, err := object(handle)
+	b.record("WindowClientRect", handle.(*Object))
+	if err != nil {
+		return
+	}
+	return 0, 0, win.Width, win.Height, nil
+}
+
+func (b *Backend) createControl(name string, kind Kind, parent native.Handle, text string) (*Object, error) {
+	win, err := object(parent)
+	if err != nil {
+		return nil, err
+	}
+	obj := b.newObject(kind, win, text)
+	b.record(name, obj, win, text)
+	return obj, nil
+}
+
+func (b *Backend) CreateButton(parent native.Handle, title string) (native.Handle, error) {
+	return b.createControl("CreateButton", Button, parent, title)
+}
+
+func (b *Backend) SetButtonOnClickListener(handle native.Handle, onClick func()) {
+	b.record("SetButtonOnClickListener", handle.(*Object))
+	handle.(*Object).onClick = onClick
+}
+
+func (b *Backend) SetButtonLabel(handle native.Handle, label string) {
+	b.record("SetButtonLabel", handle.(*Object), label)
+	handle.(*Object).Text = label
+}
+
+func (b *Backend) GetButtonMinimumSize(handle native.Handle, label string) (width, height int, err error) {
+	b.record("GetButtonMinimumSize", handle.(*Object), label)
+	width, height = b.measure(label, true)
+	return width + ButtonEdge*2, height + ButtonEdge*2, nil
+}
+
+func (b *Backend) CreateLabel(parent native.Handle, title string) (native.Handle, error) {
+	return b.createControl("CreateLabel", Label, parent, title)
+}
+
+func (b *Backend) SetLabelText(handle native.Handle, text string) error {
+	obj, err := object(handle)
+	b.record("SetLabelText", handle.(*Object), text)
+	if err != nil {
+		return err
+	}
+	obj.Text = text
+	return nil
+}
+
+func (b *Backend) CreateTextField(parent native.Handle, initialValue string, password bool) (native.Handle, error) {
+	obj, err := b.createControl("CreateTextField", TextField, parent, initialValue)
+	if err != nil {
+		return nil, err
+	}
+	obj.Password = password
+	return obj, nil
+}
+
+func (b *Backend) GetTextFieldText(handle native.Handle) (string, error) {
+	obj, err := object(handle)
+	b.record("GetTextFieldText", handle.(*Object))
+	if err != nil {
+		return "", err
+	}
+	return obj.Text, nil
+}
+
+func (b *Backend) SetTextFieldText(handle native.Handle, text string) error {
+	obj, err := object(handle)
+	b.record("SetTextFieldText", handle.(*Object), text)
+	if err != nil {
+		return err
+	}
+	obj.Text = text
+	return nil
+}
+
+func (b *Backend) SetWidgetDimensions(handle native.Handle, x, y, width, height int) error {
+	obj, err := object(handle)
+	b.record("SetWidgetDimensions", handle.(*Object), x, y, width, height)
+	if err != nil {
+		return err
+	}
+	obj.X, obj.Y, obj.Width, obj.Height = x, y, width, height
+	return nil
+}
+
+func (b *Backend) SetWidgetSize(handle native.Handle, width, height int) error {
+	obj, err := object(handle)
+	b.record("SetWidgetSize", handle.(*Object), width, height)
+	if err != nil {
+		return err
+	}
+	obj.Width, obj.Height = width, height
+	return nil
+}
+
+func (b *Backend) SetWidgetZOrder(handle, after native.Handle) error {
+	obj, err := object(handle)
+	var afterObj *Object
+	if after != nil {
+		afterObj = after.(*Object)
+	}
+	b.record("SetWidgetZOrder", handle.(*Object), afterObj)
+	if err != nil {
+		return err
+	}
+	if afterObj != nil && afterObj.Parent != obj.Parent {
+		return fmt.Errorf("%v and %v are not in the same window", obj, afterObj)
+	}
+	children := slices.DeleteFunc(obj.Parent.Children, func(o *Object) bool { return o == obj })
+	i := slices.Index(children, afterObj) + 1 // 0 if afterObj is nil.
+	obj.Parent.Children = slices.Insert(children, i, obj)
+	return nil
+}
+
+func (b *Backend) GetTextDrawingSize(control native.Handle, text string, multiline bool) (width, height int, err error) {
+	b.record("GetTextDrawingSize", control.(*Object), text, multiline)
+	width, height = b.measure(text, multiline)
+	return
+}
+
+func (b *Backend) GetTextMetrics(control native.Handle) (ascent, lineHeight int, err error) {
+	b.record("GetTextMetrics", control.(*Object))
+	_, lineHeight = b.measure("", false)
+	if ascent = b.TextAscent; ascent == 0 {
+		ascent = Ascent
+	}
+	return
+}
+
+func (b *Backend) GetTextTop(control native.Handle, height int) (top int, err error) {
+	obj := control.(*Object)
+	b.record("GetTextTop", obj, height)
+	if b.TextTop != nil {
+		return b.TextTop(obj, height), nil
+	}
+	if obj.Kind == Button {
+		_, lineHeight := b.measure("", false)
+		top = (height - lineHeight) / 2
+	}
+	return
+}
+
+// measure measures text with b.MeasureText, or with the fixed metrics if b.MeasureText is nil.
+func (b *Backend) measure(text string, multiline bool) (width, height int) {
+	if b.MeasureText != nil {
+		return b.MeasureText(text, multiline)
+	}
+	return measure(text, multiline)
+}
+
+// measure returns the size of text drawn with the fixed metrics.
+func measure(text string, multiline bool) (width, height int) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !multiline {
+		text = strings.ReplaceAll(text, "\n", "")
+		return utf8.RuneCountInString(text) * CharWidth, LineHeight
+	}
+	for line := range strings.SplitSeq(text, "\n") {
+		width = max(width, utf8.RuneCountInString(line)*CharWidth)
+		height += LineHeight
+	}
+	return
+}
+
+func (b *Backend) MessageBox(parent native.Handle, title, message string, icon native.MessageBoxIcon) {
+	var obj *Object
+	if parent != nil {
+		obj = parent.(*Object)
+	}
+	b.record("MessageBox", obj, title, message, icon)
+}
+
+func (b *Backend) EnableDrawDebugRect(winHandle native.Handle, rects func() iter.Seq[native.DebugRect]) error {
+	win, err := object(winHandle)
+	b.record("EnableDrawDebugRect", winHandle.(*Object))
+	if err != nil {
+		return err
+	}
+	win.debugRects = rects
+	return nil
+}
@@ -0,0 +1,119 @@
+package headless
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native"
+)
+
+func TestBackend_Controls(t *testing.T) {
+	b := New()
+	win, err := b.CreateWindow("title", 300, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	btn, err := b.CreateButton(win, "OK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clicked int
+	b.SetButtonOnClickListener(btn, func() { clicked++ })
+	btn.(*Object).Click()
+	if clicked != 1 {
+		t.Fatalf("clicked = %d, want 1", clicked)
+	}
+	if err = b.SetWidgetDimensions(btn, 1, 2, 3, 4); err != nil {
+		t.Fatal(err)
+	}
+	if obj := btn.(*Object); obj.X != 1 || obj.Y != 2 || obj.Width != 3 || obj.Height != 4 {
+		t.Fatalf("unexpected bounds: %v %v %v %v", obj.X, obj.Y, obj.Width, obj.Height)
+	}
+	if width, height, _ := b.GetTextDrawingSize(btn, "ab\r\nc", true); width != 2*CharWidth || height != 2*LineHeight {
+		t.Fatalf("unexpected text size: %v %v", width, height)
+	}
+	if ascent, lineHeight, _ := b.GetTextMetrics(btn); ascent != Ascent || lineHeight != LineHeight {
+		t.Fatalf("unexpected text metrics: %v %v", ascent, lineHeight)
+	}
+	if top, _ := b.GetTextTop(btn, 4*LineHeight); top != 3*LineHeight/2 {
+		t.Fatalf("unexpected text top: %v", top)
+	}
+	if err = b.DestroyWindow(btn); err != nil {
+		t.Fatal(err)
+	}
+	if len(win.(*Object).Children) != 0 {
+		t.Fatalf("destroyed control is not removed from window")
+	}
+	if err = b.SetLabelText(btn, "x"); err == nil {
+		t.Fatalf("expected error operating destroyed object")
+	}
+
+	var ops []string
+	for _, op := range b.Ops() {
+		ops = append(ops, op.String())
+	}
+	want := []string{
+		`CreateWindow(window#1, "title", 300, 200)`,
+		`CreateButton(button#2, window#1, "OK")`,
+		`SetButtonOnClickListener(button#2)`,
+		`SetWidgetDimensions(button#2, 1, 2, 3, 4)`,
+		`GetTextDrawingSize(button#2, "ab\r\nc", true)`,
+		`GetTextMetrics(button#2)`,
+		`GetTextTop(button#2, 64)`,
+		`DestroyWindow(button#2)`,
+		`SetLabelText(button#2, "x")`,
+	}
+	if !slices.Equal(ops, want) {
+		t.Fatalf("unexpected ops:\n%q\nwant:\n%q", ops, want)
+	}
+}
+
+func TestBackend_Run(t *testing.T) {
+	var b native.Backend = New()
+	var seq []int
+	b.Post(func() {
+		seq = append(seq, 1)
+		b.Post(func() {
+			seq = append(seq, 3)
+			b.Quit(7)
+		})
+	})
+	b.Post(func() { seq = append(seq, 2) })
+	if code := b.Run(); code != 7 {
+		t.Fatalf("exit code = %d, want 7", code)
+	}
+	if !slices.Equal(seq, []int{1, 2, 3}) {
+		t.Fatalf("unexpected execution order %v", seq)
+	}
+}
+
+func TestBackend_SetWidgetZOrder(t *testing.T) {
+	b := New()
+	win, _ := b.CreateWindow("", 100, 100)
+	var controls []native.Handle
+	for _, text := range []string{"a", "b", "c"} {
+		label, err := b.CreateLabel(win, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		controls = append(controls, label)
+	}
+	order := func() (texts string) {
+		for _, obj := range win.(*Object).Children {
+			texts += obj.Text
+		}
+		return
+	}
+	if err := b.SetWidgetZOrder(controls[2], nil); err != nil {
+		t.Fatal(err)
+	}
+	if order() != "cab" {
+		t.Fatalf("unexpected order %q", order())
+	}
+	if err := b.SetWidgetZOrder(controls[0], controls[1]); err != nil {
+		t.Fatal(err)
+	}
+	if order() != "cba" {
+		t.Fatalf("unexpected order %q", order())
+	}
+}
@@ -5,16 +5,7 @@ import (
 	"github.com/mkch/gw/win32/win32util"
 )
 
-type MessageBoxIcon int
-
-const (
-	MessageBoxNone MessageBoxIcon = iota
-	MessageBoxIconInfo
-	MessageBoxIconWarning
-	MessageBoxIconError
-)
-
-func MessageBox(parent Handle, title, message string, icon MessageBoxIcon) {
+func (b *win32Backend) MessageBox(parent Handle, title, message string, icon MessageBoxIcon) {
 	var nativeParent win32.HWND
 	if parent != nil {
 		nativeParent = parent.(winBase).HWND()
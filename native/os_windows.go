@@ -19,17 +19,31 @@ import (
 	"github.com/mkch/gw/window"
 )
 
-type App = *gwapp.GwApp
+// win32Backend is the [Backend] implemented with win32 API.
+type win32Backend struct {
+	app *gwapp.GwApp
+}
+
+// NewDefaultBackend returns a new instance of the default [Backend] of the platform,
+// or nil if the platform has no default backend.
+func NewDefaultBackend() Backend {
+	return &win32Backend{app: gwapp.New()}
+}
+
+func (b *win32Backend) Post(f func()) error {
+	return b.app.Post(f)
+}
 
-func NewApp() App {
-	return gwapp.New()
+func (b *win32Backend) Run() int {
+	return b.app.Run()
 }
 
-// Handle represents a platform-specific GUI object.
-type Handle any
+func (b *win32Backend) Quit(exitCode int) {
+	b.app.Quit(exitCode)
+}
 
 // CreateWindow creates a native window with the specified configuration.
-func CreateWindow(title string, width, height int) (handle Handle, err error) {
+func (b *win32Backend) CreateWindow(title string, width, height int) (handle Handle, err error) {
 	win, err := window.New(&window.Spec{
 		Text:   title,
 		Style:  win32.WS_OVERLAPPEDWINDOW | win32.WS_VISIBLE,
@@ -46,7 +60,7 @@ func CreateWindow(title string, width, height int) (handle Handle, err error) {
 	return
 }
 
-func InvalidWindow(handle Handle) error {
+func (b *win32Backend) InvalidWindow(handle Handle) error {
 	err := handle.(*window.Window).InvalidateRect(nil, true)
 	return errortrace.WithStack(err)
 }
@@ -55,12 +69,12 @@ type winBase interface {
 	HWND() win32.HWND
 }
 
-func DestroyWindow(handle Handle) error {
+func (b *win32Backend) DestroyWindow(handle Handle) error {
 	err := win32.DestroyWindow(handle.(winBase).HWND())
 	return errortrace.WithStack(err)
 }
 
-func CreateButton(parent Handle, title string) (handle Handle, err error) {
+func (b *win32Backend) CreateButton(parent Handle, title string) (handle Handle, err error) {
 	handle, err = button.New(parent.(winBase).HWND(), &button.Spec{
 		Style:  win32.WS_CHILD | win32.WS_VISIBLE,
 		Text:   title,
@@ -71,17 +85,17 @@ func CreateButton(parent Handle, title string) (handle Handle, err error) {
 	return
 }
 
-func SetButtonOnClickListener(handle Handle, onClick func()) {
+func (b *win32Backend) SetButtonOnClickListener(handle Handle, onClick func()) {
 	btn := handle.(*button.Button)
 	btn.OnClick = onClick
 }
 
-func SetButtonLabel(handle Handle, label string) {
+func (b *win32Backend) SetButtonLabel(handle Handle, label string) {
 	btn := handle.(*button.Button)
 	btn.SetText(label)
 }
 
-func CreateLabel(parent Handle, title string) (handle Handle, err error) {
+func (b *win32Backend) CreateLabel(parent Handle, title string) (handle Handle, err error) {
 	handle, err = static.New(parent.(winBase).HWND(), &static.Spec{
 		Style:  win32.WS_CHILD | win32.WS_VISIBLE,
 		Text:   title,
@@ -92,12 +106,12 @@ func CreateLabel(parent Handle, title string) (handle Handle, err error) {
 	return
 }
 
-func SetLabelText(handle Handle, text string) error {
+func (b *win32Backend) SetLabelText(handle Handle, text string) error {
 	err := handle.(*static.Static).SetText(text)
 	return errortrace.WithStack(err)
 }
 
-func CreateTextField(parent Handle, initialValue string, password bool) (handle Handle, err error) {
+func (b *win32Backend) CreateTextField(parent Handle, initialValue string, password bool) (handle Handle, err error) {
 	style := win32.WS_CHILD | win32.WS_VISIBLE | win32.WS_BORDER | edit.ES_LEFT
 	if password {
 		// Password EDIT control must be single line.
@@ -115,18 +129,18 @@ func CreateTextField(parent Handle, initialValue string, password bool) (handle
 	return
 }
 
-func GetTextFieldText(handle Handle) (text string, err error) {
+func (b *win32Backend) GetTextFieldText(handle Handle) (text string, err error) {
 	text, err = handle.(*edit.Edit).Text()
 	err = errortrace.WithStack(err)
 	return
 }
 
-func SetTextFieldText(handle Handle, text string) error {
+func (b *win32Backend) SetTextFieldText(handle Handle, text string) error {
 	err := handle.(*edit.Edit).SetText(text)
 	return errortrace.WithStack(err)
 }
 
-func SetWidgetDimensions(handle Handle, x, y, width, height int) error {
+func (b *win32Backend) SetWidgetDimensions(handle Handle, x, y, width, height int) error {
 	err := win32.SetWindowPos(handle.(winBase).HWND(), win32.HWND(0),
 		win32.INT(x), win32.INT(y),
 		win32.INT(width), win32.INT(height),
@@ -134,7 +148,7 @@ func SetWidgetDimensions(handle Handle, x, y, width, height int) error {
 	return errortrace.WithStack(err)
 }
 
-func SetWidgetSize(handle Handle, width, height int) error {
+func (b *win32Backend) SetWidgetSize(handle Handle, width, height int) error {
 	err := win32.SetWindowPos(handle.(winBase).HWND(), win32.HWND(0),
 		0, 0,
 		win32.INT(width), win32.INT(height),
@@ -142,7 +156,18 @@ func SetWidgetSize(handle Handle, width, height int) error {
 	return errortrace.WithStack(err)
 }
 
-func SetWindowOnSizeChangedListener(handle Handle, onSizeChanged func(width, height int)) {
+func (b *win32Backend) SetWidgetZOrder(handle, after Handle) error {
+	insertAfter := win32.HWND(0) // HWND_TOP
+	if after != nil {
+		insertAfter = after.(winBase).HWND()
+	}
+	err := win32.SetWindowPos(handle.(winBase).HWND(), insertAfter,
+		0, 0, 0, 0,
+		win32.SWP_NOMOVE|win32.SWP_NOSIZE|win32.SWP_NOACTIVATE)
+	return errortrace.WithStack(err)
+}
+
+func (b *win32Backend) SetWindowOnSizeChangedListener(handle Handle, onSizeChanged func(width, height int)) {
 	win := handle.(*window.Window)
 	win.AddMsgListener(win32.WM_SIZE, func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) {
 		width := win32.LOWORD(uintptr(lParam))
@@ -151,12 +176,12 @@ func SetWindowOnSizeChangedListener(handle Handle, onSizeChanged func(width, hei
 	})
 }
 
-func SetWindowOnCloseListener(handle Handle, onClose func()) {
+func (b *win32Backend) SetWindowOnCloseListener(handle Handle, onClose func()) {
 	win := handle.(*window.Window)
 	win.OnClose = onClose
 }
 
-func WindowClientRect(handle Handle) (x, y, width, height int, err error) {
+func (b *win32Backend) WindowClientRect(handle Handle) (x, y, width, height int, err error) {
 	win := handle.(*window.Window)
 	var rect win32.RECT
 	err = win32.GetClientRect(win.HWND(), &rect)
@@ -190,26 +215,7 @@ var getSystemMetricsYEdge = func() func() int {
 // GetTextDrawingSize returns the size required to draw the specified text
 // in the given control.
 // If multiline is true, the line ending characters are considered as line breaks.
-func GetTextDrawingSize(control Handle, text string, multiline bool) (width, height int, err error) {
-	win := control.(winBase)
-	hdc, err := win32.GetDC(win.HWND())
-	if err != nil {
-		err = errortrace.WithStack(err)
-		return
-	}
-	defer win32.ReleaseDC(win.HWND(), hdc)
-	font, err := win32.SendMessageW(win.HWND(), win32.WM_GETFONT, 0, 0)
-	if err != nil {
-		err = errortrace.WithStack(err)
-		return
-	}
-	oldFont, err := win32.SelectObject(hdc, win32.HFONT(font))
-	if err != nil {
-		err = errortrace.WithStack(err)
-		return
-	}
-	defer win32.SelectObject(hdc, oldFont)
-
+func (b *win32Backend) GetTextDrawingSize(control Handle, text string, multiline bool) (width, height int, err error) {
 	format := win32.DT_CALCRECT
 	if !multiline {
 		format |= win32.DT_SINGLELINE
@@ -219,9 +225,12 @@ func GetTextDrawingSize(control Handle, text string, multiline bool) (width, hei
 	win32util.CString(text, &buf)
 	const MAX_SIZE = 1<<(unsafe.Sizeof(win32.LONG(0))*8-1) - 1
 	rect := win32.RECT{Left: 0, Top: 0, Right: MAX_SIZE, Bottom: MAX_SIZE}
-	_, err = win32.DrawTextExW(hdc, &buf[0], -1,
-		&rect,
-		format, nil)
+	err = withControlFont(control, func(hdc win32.HDC) (err error) {
+		_, err = win32.DrawTextExW(hdc, &buf[0], -1,
+			&rect,
+			format, nil)
+		return
+	})
 	if err != nil {
 		err = errortrace.WithStack(err)
 		return
@@ -229,14 +238,69 @@ func GetTextDrawingSize(control Handle, text string, multiline bool) (width, hei
 	return int(rect.Width()), int(rect.Height()), nil
 }
 
-func GetButtonMinimumSize(handle Handle, label string) (width, height int, err error) {
+func (b *win32Backend) GetTextMetrics(control Handle) (ascent, lineHeight int, err error) {
+	var metrics win32.TEXTMETRICW
+	err = withControlFont(control, func(hdc win32.HDC) error {
+		return win32.GetTextMetricsW(hdc, &metrics)
+	})
+	if err != nil {
+		err = errortrace.WithStack(err)
+		return
+	}
+	return int(metrics.TmAscent), int(metrics.TmHeight), nil
+}
+
+func (b *win32Backend) GetTextTop(control Handle, height int) (top int, err error) {
+	switch control := control.(type) {
+	case *button.Button:
+		// Push buttons center the label vertically.
+		var lineHeight int
+		if _, lineHeight, err = b.GetTextMetrics(control); err != nil {
+			return
+		}
+		return (height - lineHeight) / 2, nil
+	case *edit.Edit:
+		// Single line EDIT controls draw the text at the top of the formatting
+		// rectangle, which is in the client area inside the border.
+		var rect win32.RECT
+		if _, err = win32.SendMessageW(control.HWND(), win32.EM_GETRECT, 0, win32.LPARAM(uintptr(unsafe.Pointer(&rect)))); err != nil {
+			err = errortrace.WithStack(err)
+			return
+		}
+		border := win32.GetSystemMetrics(win32.SystemMetricsIndex(win32.SM_CYBORDER))
+		return int(border) + int(rect.Top), nil
+	}
+	return 0, nil // Static controls draw the text at the top.
+}
+
+// withControlFont calls f with the device context of control, in which the font of control is selected.
+func withControlFont(control Handle, f func(hdc win32.HDC) error) error {
+	win := control.(winBase)
+	hdc, err := win32.GetDC(win.HWND())
+	if err != nil {
+		return err
+	}
+	defer win32.ReleaseDC(win.HWND(), hdc)
+	font, err := win32.SendMessageW(win.HWND(), win32.WM_GETFONT, 0, 0)
+	if err != nil {
+		return err
+	}
+	oldFont, err := win32.SelectObject(hdc, win32.HFONT(font))
+	if err != nil {
+		return err
+	}
+	defer win32.SelectObject(hdc, oldFont)
+	return f(hdc)
+}
+
+func (b *win32Backend) GetButtonMinimumSize(handle Handle, label string) (width, height int, err error) {
 	btn := handle.(*button.Button)
 	style, err := win32.GetWindowLongPtrW(btn.HWND(), win32.GWL_STYLE)
 	if err != nil {
 		err = errortrace.WithStack(err)
 		return
 	}
-	width, height, err = GetTextDrawingSize(handle, label, style&win32.BS_MULTILINE != 0)
+	width, height, err = b.GetTextDrawingSize(handle, label, style&win32.BS_MULTILINE != 0)
 	if err != nil {
 		err = errortrace.WithStack(err)
 		return
@@ -277,12 +341,7 @@ var debugRectHighlightBrush = func() func() *brush.Brush {
 	}
 }()
 
-type DebugRect struct {
-	Left, Top, Right, Bottom int
-	Highlight                bool
-}
-
-func EnableDrawDebugRect(winHandle Handle, rects func() iter.Seq[DebugRect]) error {
+func (b *win32Backend) EnableDrawDebugRect(winHandle Handle, rects func() iter.Seq[DebugRect]) error {
 	win := winHandle.(*window.Window)
 	win.SetPaintCallback(func(dc *paint.PaintDC, prev func(*paint.PaintDC)) {
 		pen := debugRectPen()
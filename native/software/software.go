@@ -0,0 +1,143 @@
+// Package software provides a [native.Backend] that paints windows and controls
+// into images in pure Go. Text is drawn with a built-in bitmap font, so the output
+// is deterministic on every platform.
+package software
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+// Colors used to paint windows and controls.
+var (
+	WindowColor          = color.RGBA{0xF0, 0xF0, 0xF0, 0xFF}
+	TextColor            = color.RGBA{0x00, 0x00, 0x00, 0xFF}
+	ButtonFaceColor      = color.RGBA{0xE1, 0xE1, 0xE1, 0xFF}
+	ButtonBorderColor    = color.RGBA{0xAD, 0xAD, 0xAD, 0xFF}
+	TextFieldColor       = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	TextFieldBorderColor = color.RGBA{0x7A, 0x7A, 0x7A, 0xFF}
+	DebugRectColor       = color.RGBA{0xFF, 0x00, 0x00, 0xFF}
+)
+
+// textFieldMargin is the horizontal margin between the border and the text of a text field.
+const textFieldMargin = 2
+
+// Backend is a [headless.Backend] that can paint its windows with [Backend.Render].
+// Text is measured with the built-in font.
+// Create a Backend with [New].
+type Backend struct {
+	*headless.Backend
+}
+
+// New creates a new software backend.
+func New() *Backend {
+	backend := headless.New()
+	backend.MeasureText = MeasureText
+	backend.TextAscent = Ascent
+	backend.TextTop = textTop
+	return &Backend{backend}
+}
+
+// Render paints the client area of window and returns the image.
+// Controls are painted in the order of window.Children, and debug rects, if enabled, are painted on top of them.
+func (b *Backend) Render(window *headless.Object) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, window.Width, window.Height))
+	fillRect(img, img.Bounds(), WindowColor)
+	for _, control := range window.Children {
+		bounds := image.Rect(control.X, control.Y, control.X+control.Width, control.Y+control.Height)
+		switch control.Kind {
+		case headless.Button:
+			paintButton(img, bounds, control)
+		case headless.Label:
+			// Single line, as the label is measured.
+			drawText(img, bounds, bounds.Min.X, bounds.Min.Y, control.Text, false, TextColor)
+		case headless.TextField:
+			paintTextField(img, bounds, control)
+		}
+	}
+	for _, rect := range window.DebugRects() {
+		bounds := image.Rect(rect.Left, rect.Top, rect.Right, rect.Bottom)
+		if rect.Highlight {
+			fillRect(img, bounds, DebugRectColor)
+		}
+		dottedRect(img, bounds, DebugRectColor)
+	}
+	return img
+}
+
+// textTop returns the distance from the top of control, which is height high,
+// to the top of its text. The text is vertically centered in buttons and text fields,
+// and drawn at the top of labels.
+func textTop(control *headless.Object, height int) int {
+	switch control.Kind {
+	case headless.Button:
+		_, textHeight := MeasureText(control.Text, true)
+		return (height - textHeight) / 2
+	case headless.TextField:
+		return (height - LineHeight) / 2
+	}
+	return 0
+}
+
+// paintButton paints a push button with centered label.
+func paintButton(img *image.RGBA, bounds image.Rectangle, button *headless.Object) {
+	fillRect(img, bounds, ButtonFaceColor)
+	strokeRect(img, bounds, ButtonBorderColor)
+	width, _ := MeasureText(button.Text, true)
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + textTop(button, bounds.Dy())
+	drawText(img, bounds.Inset(1), x, y, button.Text, true, TextColor)
+}
+
+// paintTextField paints a single line text field with vertically centered text.
+func paintTextField(img *image.RGBA, bounds image.Rectangle, field *headless.Object) {
+	fillRect(img, bounds, TextFieldColor)
+	strokeRect(img, bounds, TextFieldBorderColor)
+	text := field.Text
+	if field.Password {
+		text = strings.Repeat("*", utf8.RuneCountInString(text))
+	}
+	y := bounds.Min.Y + textTop(field, bounds.Dy())
+	drawText(img, bounds.Inset(1), bounds.Min.X+1+textFieldMargin, y, text, false, TextColor)
+}
+
+// fillRect fills r with c.
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// strokeRect draws the 1 pixel wide border inside r.
+func strokeRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	if r.Empty() {
+		return
+	}
+	fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), c)
+	fillRect(img, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), c)
+	fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y), c)
+	fillRect(img, image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y), c)
+}
+
+// dottedRect draws the 1 pixel wide dotted border inside r.
+func dottedRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	if r.Empty() {
+		return
+	}
+	dot := func(x, y int) {
+		if (x+y)%2 == 0 && image.Pt(x, y).In(img.Bounds()) {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	for x := r.Min.X; x < r.Max.X; x++ {
+		dot(x, r.Min.Y)
+		dot(x, r.Max.Y-1)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		dot(r.Min.X, y)
+		dot(r.Max.X-1, y)
+	}
+}
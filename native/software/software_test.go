@@ -0,0 +1,57 @@
+package software
+
+import (
+	"image"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native"
+	"github.com/mkch/goui/native/headless"
+)
+
+func TestMeasureText(t *testing.T) {
+	if len(glyphs) != '~'-' '+1 {
+		t.Fatalf("font has %d glyphs", len(glyphs))
+	}
+	if w, h := MeasureText("abc\r\nde", true); w != 3*CellWidth || h != 2*LineHeight {
+		t.Fatalf("unexpected multiline size %v %v", w, h)
+	}
+	if w, h := MeasureText("abc\r\nde", false); w != 5*CellWidth || h != LineHeight {
+		t.Fatalf("unexpected single line size %v %v", w, h)
+	}
+}
+
+func TestRender(t *testing.T) {
+	b := New()
+	win, _ := b.CreateWindow("test", 100, 50)
+	btn, _ := b.CreateButton(win, "I")
+	b.SetWidgetDimensions(btn, 10, 10, 20, 20)
+	if w, _, _ := b.GetButtonMinimumSize(btn, "II"); w != 2*CellWidth+2*headless.ButtonEdge {
+		t.Fatalf("button is not measured with the built-in font: %v", w)
+	}
+	label, _ := b.CreateLabel(win, "I\nI")
+	b.SetWidgetDimensions(label, 70, 10, 2*CellWidth, LineHeight)
+	b.EnableDrawDebugRect(win, func() iter.Seq[native.DebugRect] {
+		return slices.Values([]native.DebugRect{{Left: 50, Top: 10, Right: 60, Bottom: 20, Highlight: true}})
+	})
+
+	img := b.Render(win.(*headless.Object))
+	if img.Bounds() != image.Rect(0, 0, 100, 50) {
+		t.Fatalf("unexpected image bounds %v", img.Bounds())
+	}
+	check := func(x, y int, want any) {
+		t.Helper()
+		if got := img.RGBAAt(x, y); got != want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+		}
+	}
+	check(0, 0, WindowColor)
+	check(10, 10, ButtonBorderColor)
+	check(11, 11, ButtonFaceColor)
+	// 'I' is centered in the button. Its middle column is a vertical bar.
+	check(10+(20-CellWidth)/2+2, 10+(20-LineHeight)/2+glyphOffsetY+3, TextColor)
+	check(55, 15, DebugRectColor)
+	// The label is single line, the second 'I' follows the first one.
+	check(70+CellWidth+2, 10+glyphOffsetY+3, TextColor)
+}
@@ -0,0 +1,106 @@
+package goui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// PatchOp is the kind of a [Patch].
+type PatchOp int
+
+const (
+	PatchCreate  PatchOp = iota // A new element is built for the widget.
+	PatchUpdate                 // The element is updated in place to hold the widget.
+	PatchMove                   // The element is updated in place and moved among its siblings.
+	PatchDestroy                // The element is destroyed.
+)
+
+func (op PatchOp) String() string {
+	switch op {
+	case PatchCreate:
+		return "create"
+	case PatchUpdate:
+		return "update"
+	case PatchMove:
+		return "move"
+	case PatchDestroy:
+		return "destroy"
+	default:
+		return fmt.Sprintf("PatchOp(%d)", int(op))
+	}
+}
+
+// Patch is an operation performed on a child of an element by reconciliation.
+type Patch struct {
+	Op   PatchOp
+	ID   ID           // The ID of the widget, or nil if the widget has no ID.
+	Type reflect.Type // The type of the widget.
+	// OldIndex is the index of the child before the reconciliation, or -1 for PatchCreate.
+	OldIndex int
+	// NewIndex is the index of the child after the reconciliation, or -1 for PatchDestroy.
+	NewIndex int
+}
+
+func (p Patch) String() string {
+	widget := p.Type.String()
+	if p.ID != nil {
+		widget += fmt.Sprintf("[%v]", p.ID)
+	}
+	switch p.Op {
+	case PatchCreate:
+		return fmt.Sprintf("%v %v at %d", p.Op, widget, p.NewIndex)
+	case PatchDestroy:
+		return fmt.Sprintf("%v %v at %d", p.Op, widget, p.OldIndex)
+	default:
+		return fmt.Sprintf("%v %v %d->%d", p.Op, widget, p.OldIndex, p.NewIndex)
+	}
+}
+
+// newPatch returns a patch of op on the child holding widget.
+func newPatch(op PatchOp, widget Widget, oldIndex, newIndex int) Patch {
+	return Patch{Op: op, ID: widget.WidgetID(), Type: reflect.TypeOf(widget), OldIndex: oldIndex, NewIndex: newIndex}
+}
+
+// ChildPatcher is implemented by elements that need to know how their children
+// are changed by reconciliation, for example to keep the native z-order in sync.
+type ChildPatcher interface {
+	Element
+	// PatchChildren is called after the children of the element are reconciled.
+	// The PatchDestroy patches come first in the order of OldIndex, followed by
+	// the others in the order of NewIndex.
+	PatchChildren(ctx *Context, patches []Patch) error
+}
+
+// patchesWanted reports whether the patches of the children of elem are wanted.
+func patchesWanted(ctx *Context, elem Element) bool {
+	_, ok := elem.(ChildPatcher)
+	return ok || ctx.app.onPatch != nil
+}
+
+// applyPatches sorts patches of the children of elem and passes them to
+// [AppConfig.OnPatch] and [ChildPatcher].
+func applyPatches(ctx *Context, elem Element, patches []Patch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(patches, func(a, b Patch) int {
+		if a.Op == PatchDestroy || b.Op == PatchDestroy {
+			if a.Op != b.Op {
+				if a.Op == PatchDestroy {
+					return -1
+				}
+				return 1
+			}
+			return a.OldIndex - b.OldIndex
+		}
+		return a.NewIndex - b.NewIndex
+	})
+	if ctx.app.onPatch != nil {
+		ctx.app.onPatch(ctx, elem, patches)
+	}
+	if patcher, ok := elem.(ChildPatcher); ok {
+		return patcher.PatchChildren(ctx, patches)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package goui
+
+import (
+	"slices"
+	"testing"
+)
+
+// patchingElement records the patches of its children.
+type patchingElement struct {
+	ElementBase
+	patches []string
+}
+
+func (e *patchingElement) PatchChildren(ctx *Context, patches []Patch) error {
+	for _, p := range patches {
+		e.patches = append(e.patches, p.String())
+	}
+	return nil
+}
+
+func TestUpdateContainerElement_Patches(t *testing.T) {
+	var onPatch []Patch
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, OnPatch: func(ctx *Context, parent Element, patches []Patch) {
+		onPatch = append(onPatch, patches...)
+	}})
+	leaf := func(id string) Widget {
+		return &mockWidget{ID: ValueID(id), element: &ElementBase{ElementLayouter: &mockLayouter{}}}
+	}
+	elem := &patchingElement{ElementBase: ElementBase{ElementLayouter: &countingLayouter{}}}
+
+	root, _, err := buildElementTree(ctx, &patchingContainer{element: elem, children: []Widget{leaf("a"), leaf("b"), leaf("c"), leaf("d")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(elem.patches) != 0 || len(onPatch) != 0 {
+		t.Fatalf("unexpected patches of the initial build: %v", elem.patches)
+	}
+	_, _, err = reconcileElementTree(ctx, root, &patchingContainer{element: elem, children: []Widget{leaf("a"), leaf("c"), leaf("x"), leaf("b")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"destroy *goui.mockWidget[d] at 3",
+		"update *goui.mockWidget[a] 0->0",
+		"move *goui.mockWidget[c] 2->1",
+		"create *goui.mockWidget[x] at 2",
+		"update *goui.mockWidget[b] 1->3",
+	}
+	if !slices.Equal(elem.patches, expected) {
+		t.Fatalf("expected patches %q, got %q", expected, elem.patches)
+	}
+	if len(onPatch) != len(expected) {
+		t.Fatalf("expected %d patches passed to OnPatch, got %d", len(expected), len(onPatch))
+	}
+}
+
+// patchingContainer is a container of a patchingElement.
+type patchingContainer struct {
+	element  *patchingElement
+	children []Widget
+}
+
+func (c *patchingContainer) WidgetID() ID {
+	return nil
+}
+
+func (c *patchingContainer) CreateElement(ctx *Context) (Element, error) {
+	return c.element, nil
+}
+
+func (c *patchingContainer) NumChildren() int {
+	return len(c.children)
+}
+
+func (c *patchingContainer) Child(n int) Widget {
+	return c.children[n]
+}
+
+func (c *patchingContainer) Exclusive(Container) { /*Nop*/ }
@@ -87,7 +87,7 @@ func userPass(userNameCtrl, passwordCtrl *widgets.TextFieldController) goui.Widg
 				Width:  rowWidth,
 				Height: rowHeight,
 				Widget: &widgets.Row{
-					CrossAxisAlignment: axes.Center,
+					CrossAxisAlignment: axes.Baseline,
 					Widgets: []goui.Widget{
 						&widgets.Expanded{
 							Flex: 1,
@@ -115,7 +115,7 @@ func userPass(userNameCtrl, passwordCtrl *widgets.TextFieldController) goui.Widg
 				Width:  rowWidth,
 				Height: rowHeight,
 				Widget: &widgets.Row{
-					CrossAxisAlignment: axes.Center,
+					CrossAxisAlignment: axes.Baseline,
 					Widgets: []goui.Widget{
 						&widgets.Expanded{
 							Flex: 1,
@@ -0,0 +1,222 @@
+// Package signal provides reactive values. A [Signal] holds a value, a [Computed]
+// derives a value from other signals, and an [Effect] runs a function whenever the
+// signals it reads change.
+//
+// Reads are tracked by the running [Tracker]. Goui runs the Build of every
+// stateless and stateful widget with a Tracker, so writing a signal schedules
+// a rebuild of exactly the widgets which read it in their last build.
+//
+// Signals are not safe for concurrent use. Signals read in Build must only be
+// accessed on the GUI goroutine, use goui.App.Post from other goroutines.
+package signal
+
+import (
+	"reflect"
+	"slices"
+)
+
+// current is the running tracker, if any.
+var current *Tracker
+
+// batchDepth is the depth of nested writes, effects are run when it drops to 0.
+var batchDepth int
+
+// pendingEffects are the effects to be run at the end of the outermost write.
+var pendingEffects []*Effect
+
+// source is a value that can be tracked.
+type source struct {
+	subs []*Tracker
+}
+
+// track records that the running tracker depends on s.
+func (s *source) track() {
+	if current != nil && !slices.Contains(current.sources, s) {
+		current.sources = append(current.sources, s)
+		s.subs = append(s.subs, current)
+	}
+}
+
+// notify invalidates the trackers depending on s.
+func (s *source) notify() {
+	batchDepth++
+	for _, sub := range slices.Clone(s.subs) {
+		sub.invalidate()
+	}
+	batchDepth--
+	if batchDepth == 0 {
+		runPendingEffects()
+	}
+}
+
+func runPendingEffects() {
+	for len(pendingEffects) > 0 {
+		effect := pendingEffects[0]
+		pendingEffects = pendingEffects[1:]
+		effect.run()
+	}
+}
+
+// Tracker tracks the signals read by a function, and calls a callback when
+// any of them changes.
+// Create a Tracker with [NewTracker].
+type Tracker struct {
+	onInvalidate func()
+	sources      []*source
+}
+
+// NewTracker creates a new Tracker which calls onInvalidate when any signal
+// read in the last [Tracker.Run] changes.
+func NewTracker(onInvalidate func()) *Tracker {
+	return &Tracker{onInvalidate: onInvalidate}
+}
+
+// Run calls f and tracks the signals read by f, replacing the ones tracked
+// by the previous Run. Calls of Run can be nested, a signal is only tracked
+// by the innermost running Tracker.
+func (t *Tracker) Run(f func()) {
+	t.Stop()
+	prev := current
+	current = t
+	defer func() { current = prev }()
+	f()
+}
+
+// Stop stops tracking the signals read in the last Run.
+func (t *Tracker) Stop() {
+	for _, s := range t.sources {
+		s.subs = slices.DeleteFunc(s.subs, func(sub *Tracker) bool { return sub == t })
+	}
+	t.sources = nil
+}
+
+func (t *Tracker) invalidate() {
+	t.onInvalidate()
+}
+
+// Untracked calls f without tracking the signals read by f.
+func Untracked(f func()) {
+	prev := current
+	current = nil
+	defer func() { current = prev }()
+	f()
+}
+
+// Signal is a value whose changes are tracked.
+// Create a Signal with [New].
+type Signal[T any] struct {
+	source
+	value T
+}
+
+// New creates a new Signal with the initial value.
+func New[T any](value T) *Signal[T] {
+	return &Signal[T]{value: value}
+}
+
+// Get returns the value, and tracks s in the running [Tracker].
+func (s *Signal[T]) Get() T {
+	s.track()
+	return s.value
+}
+
+// Peek returns the value without tracking.
+func (s *Signal[T]) Peek() T {
+	return s.value
+}
+
+// Set sets the value and notifies the dependents if the value is changed.
+// Values of comparable types are compared with ==, other values are always
+// considered changed.
+func (s *Signal[T]) Set(value T) {
+	if equal(s.value, value) {
+		return
+	}
+	s.value = value
+	s.notify()
+}
+
+// Update sets the value to f(old value), see [Signal.Set].
+func (s *Signal[T]) Update(f func(T) T) {
+	s.Set(f(s.value))
+}
+
+// equal reports whether a and b are comparable and equal.
+func equal[T any](a, b T) bool {
+	v := reflect.ValueOf(any(a))
+	if !v.IsValid() {
+		return any(b) == nil
+	}
+	return v.Comparable() && any(a) == any(b)
+}
+
+// Computed is a value derived from other signals. It is computed lazily on
+// [Computed.Get], and recomputed only if the signals it read have changed.
+// Create a Computed with [NewComputed].
+type Computed[T any] struct {
+	source
+	compute func() T
+	value   T
+	stale   bool
+	tracker *Tracker
+}
+
+// NewComputed creates a new Computed with the compute function.
+func NewComputed[T any](compute func() T) *Computed[T] {
+	c := &Computed[T]{compute: compute, stale: true}
+	c.tracker = NewTracker(func() {
+		if !c.stale {
+			c.stale = true
+			c.notify()
+		}
+	})
+	return c
+}
+
+// Get returns the value, recomputing it if stale, and tracks c in the running [Tracker].
+func (c *Computed[T]) Get() T {
+	if c.stale {
+		c.tracker.Run(func() { c.value = c.compute() })
+		c.stale = false
+	}
+	c.track()
+	return c.value
+}
+
+// Effect runs a function whenever the signals it read in the last run change.
+// Create an Effect with [NewEffect].
+type Effect struct {
+	f       func()
+	tracker *Tracker
+	pending bool
+	stopped bool
+}
+
+// NewEffect creates an Effect and runs f immediately.
+// After that, f is run again at the end of every write that changes any signal
+// f read in the last run.
+func NewEffect(f func()) *Effect {
+	e := &Effect{f: f}
+	e.tracker = NewTracker(func() {
+		if !e.pending && !e.stopped {
+			e.pending = true
+			pendingEffects = append(pendingEffects, e)
+		}
+	})
+	e.run()
+	return e
+}
+
+func (e *Effect) run() {
+	e.pending = false
+	if e.stopped {
+		return
+	}
+	e.tracker.Run(e.f)
+}
+
+// Stop stops the effect. F is never run after Stop returns.
+func (e *Effect) Stop() {
+	e.stopped = true
+	e.tracker.Stop()
+}
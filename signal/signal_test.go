@@ -0,0 +1,105 @@
+package signal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSignal_Tracker(t *testing.T) {
+	a, b := New(1), New("x")
+	var invalidations int
+	tracker := NewTracker(func() { invalidations++ })
+	tracker.Run(func() {
+		a.Get()
+		b.Peek()
+	})
+	b.Set("y")
+	if invalidations != 0 {
+		t.Fatal("Peek is tracked")
+	}
+	a.Set(1)
+	if invalidations != 0 {
+		t.Fatal("setting the same value notifies")
+	}
+	a.Set(2)
+	if invalidations != 1 {
+		t.Fatalf("expected 1 invalidation, got %d", invalidations)
+	}
+
+	// A new run replaces the sources.
+	tracker.Run(func() { b.Get() })
+	a.Set(3)
+	b.Update(func(s string) string { return s + "z" })
+	if invalidations != 2 || b.Peek() != "yz" {
+		t.Fatalf("expected 2 invalidations and yz, got %d and %v", invalidations, b.Peek())
+	}
+
+	tracker.Stop()
+	b.Set("w")
+	if invalidations != 2 {
+		t.Fatal("stopped tracker is invalidated")
+	}
+}
+
+func TestSignal_Incomparable(t *testing.T) {
+	s := New([]int{1})
+	var invalidations int
+	NewTracker(func() { invalidations++ }).Run(func() { s.Get() })
+	s.Set([]int{1})
+	if invalidations != 1 {
+		t.Fatalf("expected 1 invalidation, got %d", invalidations)
+	}
+}
+
+func TestComputed(t *testing.T) {
+	a, b := New(1), New(2)
+	var computes int
+	sum := NewComputed(func() int { computes++; return a.Get() + b.Get() })
+	if computes != 0 {
+		t.Fatal("computed eagerly")
+	}
+	if sum.Get() != 3 || sum.Get() != 3 || computes != 1 {
+		t.Fatalf("expected 1 compute, got %d", computes)
+	}
+	a.Set(10)
+	if sum.Get() != 12 || computes != 2 {
+		t.Fatalf("expected 12 after 2 computes, got %v after %d", sum.value, computes)
+	}
+}
+
+func TestEffect(t *testing.T) {
+	a := New(1)
+	double := NewComputed(func() int { return a.Get() * 2 })
+	var log []int
+	effect := NewEffect(func() {
+		log = append(log, a.Get()+double.Get())
+	})
+	a.Set(2)
+	// Changing an effect's signal from an effect runs it once more.
+	NewEffect(func() {
+		if a.Get() == 3 {
+			a.Set(4)
+		}
+	})
+	a.Set(3)
+	if want := []int{3, 6, 9, 12}; !slices.Equal(log, want) {
+		t.Fatalf("expected %v, got %v", want, log)
+	}
+	effect.Stop()
+	a.Set(5)
+	if len(log) != 4 {
+		t.Fatal("stopped effect runs")
+	}
+}
+
+func TestUntracked(t *testing.T) {
+	a := New(1)
+	var invalidations int
+	NewTracker(func() { invalidations++ }).Run(func() {
+		Untracked(func() { a.Get() })
+	})
+	a.Set(2)
+	if invalidations != 0 {
+		t.Fatal("untracked read is tracked")
+	}
+}
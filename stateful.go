@@ -1,9 +1,5 @@
 package goui
 
-import (
-	"errors"
-)
-
 type StatefulWidget interface {
 	Widget
 	CreateState(*Context, UpdateStateFunc) *WidgetState
@@ -39,13 +35,46 @@ func (f StatefulWidgetFunc) CreateState(ctx *Context, updateState UpdateStateFun
 func (f StatefulWidgetFunc) Exclusive(StatefulWidget) { /*Nop*/ }
 
 type statefulElement struct {
-	ElementBase
+	componentElement
 	state *WidgetState
+	hooks hooks
+}
+
+func (e *statefulElement) SetWidget(ctx *Context, widget Widget) {
+	old := e.Widget()
+	e.componentElement.SetWidget(ctx, widget)
+	if old != nil {
+		e.state.didUpdateWidget(old, widget)
+	}
 }
 
+func (e *statefulElement) build() Widget {
+	if e.dependenciesChanged {
+		e.dependenciesChanged = false
+		e.state.didChangeDependencies()
+	}
+	e.hooks.begin()
+	// Build may panic and be recovered by an ErrorBoundary.
+	defer func() { e.hooks.building = false }()
+	widget := e.state.Build()
+	e.hooks.end()
+	return widget
+}
+
+func (e *statefulElement) didBuild() {
+	e.hooks.runEffects()
+}
+
+// destroy destroys the descendants, then the hooks and then the state.
 func (e *statefulElement) destroy() {
-	e.state.destroyData()
-	e.ElementBase.destroy()
+	if e.destroyed {
+		return
+	}
+	e.componentElement.destroy()
+	e.hooks.dispose()
+	if e.state != nil { // Nil if CreateState failed.
+		e.state.destroyData()
+	}
 }
 
 // createStatefulElement creates a new [Element] for a [StatefulWidget].
@@ -59,70 +88,113 @@ func createStatefulElement(*Context) Element {
 
 // UpdateStateFunc is a function type for updating the state of a widget.
 // Parameter f is a function that performs the state update.
-// UpdateStateFunc calls f and updates the widget accordingly.
+// UpdateStateFunc calls f and schedules the widget to be rebuilt in the next frame.
+// Multiple updates before the next frame are coalesced into a single rebuild,
+// see [App.Flush].
+//
+// UpdateStateFunc can be called from any goroutine. If it is called off the GUI goroutine,
+// the update is posted with [App.Post], f is called later on the GUI goroutine, and
+// the returned error is the error of posting. Use [UpdateStateFunc.Async] to wait for the update.
 type UpdateStateFunc func(f func()) error
 
+// Async calls update with f, and returns a channel that receives nil after f is called
+// on the GUI goroutine and the widget is scheduled to be rebuilt, or the error if the
+// update can't be posted or scheduled. The channel receives exactly one value.
+func (update UpdateStateFunc) Async(f func()) <-chan error {
+	result := make(chan error, 1)
+	// f is followed by an empty update, which is done synchronously on the GUI goroutine,
+	// so the error of scheduling is received. The scheduling after f returns does nothing,
+	// because the widget is marked dirty already.
+	if err := update(func() { f(); result <- update(func() {}) }); err != nil {
+		result <- err // Failed to post, f is not called.
+	}
+	return result
+}
+
+// WidgetState is the state of a [StatefulWidget], created by [StatefulWidget.CreateState].
+// All the functions except Build can be nil.
+//
+// The lifecycle of a state is:
+//  1. InitState, then DidChangeDependencies, then Build, when the element is created.
+//  2. DidUpdateWidget, then Build, whenever the parent is rebuilt with a matching widget.
+//  3. DidChangeDependencies, then Build, when the value of an [Inherited] widget
+//     the state depends on changes.
+//  4. Build, in the frame after the state is updated via [UpdateStateFunc].
+//  5. Deactivate, when the element is removed from the tree, and Activate if it is
+//     reinserted into the tree in the same frame.
+//  6. DestroyData, when the element is destroyed, after the cleanups of [UseEffect].
+//
+// When a subtree is removed, the states are deactivated from the root of the subtree down,
+// and then destroyed from the leaves up, so a state is destroyed after all its descendants.
 type WidgetState struct {
 	// Build builds the widget tree for this state.
-	// It is called during the initial creation of the state
-	// and whenever the state is updated via [UpdateStateFunc].
 	Build func() Widget
-	// DestroyData is called when the state is destroyed, if not nil.
+	// InitState is called once before the first Build.
+	InitState func()
+	// DidUpdateWidget is called when the element is updated to hold a new widget,
+	// before Build is called. Old and new are of the same type and ID.
+	DidUpdateWidget func(old, new Widget)
+	// DidChangeDependencies is called after InitState, and when the value of an
+	// [Inherited] widget read with [DependOn] changes, before Build is called.
+	DidChangeDependencies func()
+	// Deactivate is called when the element is removed from the tree.
+	Deactivate func()
+	// Activate is called when a deactivated element is reinserted into the tree.
+	Activate func()
+	// DestroyData is called when the state is destroyed.
 	// It can be used to clean up any resources associated with the state.
-	DestroyData func() // Can be nil
+	DestroyData func()
 }
 
-func (ws *WidgetState) destroyData() {
-	if ws.DestroyData != nil {
-		ws.DestroyData()
+func (ws *WidgetState) initState() {
+	if ws.InitState != nil {
+		ws.InitState()
 	}
 }
 
-// updateWidgetState calls f and updates its widget tree.
-// f can't be nil.
-func updateWidgetState(f func(), ctx *Context, elem *statefulElement) error {
-	f()
-	// Rebuild the child widget and reconcile.
-	newWidget := elem.state.Build()
-	reconciled, layouter, err := reconcileElementTree(ctx, elem.children[0], newWidget)
-	if err != nil {
-		return err
-	}
-	if reconciled != elem.children[0] {
-		element_SetChild(elem, 0, reconciled)
+func (ws *WidgetState) didUpdateWidget(old, new Widget) {
+	if ws.DidUpdateWidget != nil {
+		ws.DidUpdateWidget(old, new)
 	}
+}
 
-	if layouter == nil {
-		return nil
+func (ws *WidgetState) didChangeDependencies() {
+	if ws.DidChangeDependencies != nil {
+		ws.DidChangeDependencies()
 	}
+}
 
-	if err = replayParentLayouter(ctx, layouter); err == nil {
-		return nil
+func (ws *WidgetState) deactivate() {
+	if ws.Deactivate != nil {
+		ws.Deactivate()
 	}
+}
 
-	if err == errNotReplayable {
-		return layoutWindow(ctx)
+func (ws *WidgetState) activate() {
+	if ws.Activate != nil {
+		ws.Activate()
 	}
-	return err
 }
 
-var errNotReplayable = errors.New("the parent layouter does not support replaying")
-
-// replayParentLayouter replays the laying out of the nearest recursive parent
-// which supports replaying.
-// If no such parent exists, it returns errNotReplayable.
-func replayParentLayouter(ctx *Context, root Layouter) error {
-	// Find the nearest child-independent recursive parent(replayer).
-	var replayer func(*Context) error
-	for parent := root.Parent(); parent != nil; parent = parent.Parent() {
-		if replayer = parent.Replayer(); replayer != nil {
-			break
-		}
+func (ws *WidgetState) destroyData() {
+	if ws.DestroyData != nil {
+		ws.DestroyData()
 	}
-	if replayer == nil {
-		return errNotReplayable
+}
+
+// updateWidgetState calls f and schedules elem to be rebuilt.
+// If it is called off the GUI goroutine, the whole update is posted to the GUI goroutine.
+// f can't be nil.
+func updateWidgetState(f func(), elem buildableElement) error {
+	app := elem.component().ctx.app
+	if !app.onGUIGoroutine() {
+		return app.Post(func() {
+			f()
+			app.scheduleBuild(elem)
+		})
 	}
-	return replayer(ctx)
+	f()
+	return app.markNeedsBuild(elem)
 }
 
 // statelessWidget is an implementation of StatelessWidget.
@@ -0,0 +1,95 @@
+package goui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+// lifecycleWidget is a stateful widget which logs the lifecycle of its state.
+type lifecycleWidget struct {
+	StatefulWidgetImpl
+	name  string
+	log   *[]string
+	child Widget
+}
+
+func (w *lifecycleWidget) WidgetID() ID {
+	return ValueID(w.name)
+}
+
+func (w *lifecycleWidget) CreateState(ctx *Context, updateState UpdateStateFunc) *WidgetState {
+	logf := func(event string) func() {
+		return func() { *w.log = append(*w.log, w.name+"."+event) }
+	}
+	widget := w
+	return &WidgetState{
+		Build: func() Widget {
+			logf("Build")()
+			DependOn[int](ctx)
+			return widget.child
+		},
+		InitState: logf("InitState"),
+		DidUpdateWidget: func(old, new Widget) {
+			if old.(*lifecycleWidget) != widget {
+				panic("old widget mismatch")
+			}
+			widget = new.(*lifecycleWidget)
+			logf("DidUpdateWidget")()
+		},
+		DidChangeDependencies: logf("DidChangeDependencies"),
+		Deactivate:            logf("Deactivate"),
+		Activate:              logf("Activate"),
+		DestroyData:           logf("DestroyData"),
+	}
+}
+
+func TestWidgetState_Lifecycle(t *testing.T) {
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: headless.New()})
+	var log []string
+	tree := func(value int) Widget {
+		return &Inherited[int]{
+			Value: value,
+			Widget: &lifecycleWidget{name: "outer", log: &log,
+				child: &lifecycleWidget{name: "inner", log: &log,
+					child: &mockWidget{element: &ElementBase{ElementLayouter: &mockLayouter{}}},
+				},
+			},
+		}
+	}
+	expect := func(events ...string) {
+		t.Helper()
+		if !slices.Equal(log, events) {
+			t.Fatalf("expected %v, got %v", events, log)
+		}
+		log = nil
+	}
+
+	root, _, err := buildElementTree(ctx, tree(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect("outer.InitState", "outer.DidChangeDependencies", "outer.Build",
+		"inner.InitState", "inner.DidChangeDependencies", "inner.Build")
+
+	if _, _, err = reconcileElementTree(ctx, root, tree(1)); err != nil {
+		t.Fatal(err)
+	}
+	expect("outer.DidUpdateWidget", "outer.Build", "inner.DidUpdateWidget", "inner.Build")
+
+	if _, _, err = reconcileElementTree(ctx, root, tree(2)); err != nil {
+		t.Fatal(err)
+	}
+	expect("outer.DidUpdateWidget", "outer.DidChangeDependencies", "outer.Build",
+		"inner.DidUpdateWidget", "inner.DidChangeDependencies", "inner.Build")
+	if err = ctx.app.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expect() // Already rebuilt with the parent.
+
+	if _, _, err = reconcileElementTree(ctx, root, &Inherited[int]{Value: 2, Widget: &mockWidget{element: &ElementBase{}}}); err != nil {
+		t.Fatal(err)
+	}
+	expect("outer.Deactivate", "inner.Deactivate", "inner.DestroyData", "outer.DestroyData")
+}
@@ -36,7 +36,15 @@ func (f StatelessWidgetFunc) Exclusive(StatelessWidget) { /*Nop*/ }
 
 // createStatelessElement creates a new [Element] for a [StatelessWidget].
 func createStatelessElement(*Context) Element {
-	return &ElementBase{}
+	return &statelessElement{}
+}
+
+type statelessElement struct {
+	componentElement
+}
+
+func (e *statelessElement) build() Widget {
+	return e.Widget().(StatelessWidget).Build(e.ctx)
 }
 
 // statelessWidget is an implementation of StatelessWidget.
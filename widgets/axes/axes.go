@@ -10,6 +10,7 @@ const (
 	Min
 )
 
+// Alignment defines how widgets are placed in the cross axis.
 type Alignment int
 
 const (
@@ -19,4 +20,31 @@ const (
 	Center
 	// End means the widget is aligned to the end of the axis.
 	End
+	// Baseline means the widgets in a row are aligned by the baselines of their first
+	// lines of text, and the ones without baselines are aligned to the start.
+	// It is the same as Start in a column.
+	Baseline
+	// Stretch means the widgets are stretched to fill the axis, if it is bounded.
+	// It is the same as Start if the axis is unbounded.
+	Stretch
+)
+
+// MainAxisAlignment defines how the free space in the main axis is placed around the widgets.
+type MainAxisAlignment int
+
+const (
+	// MainAxisStart means the widgets are placed at the start of the axis.
+	MainAxisStart MainAxisAlignment = iota
+	// MainAxisEnd means the widgets are placed at the end of the axis.
+	MainAxisEnd
+	// MainAxisCenter means the widgets are centered in the axis.
+	MainAxisCenter
+	// SpaceBetween means the free space is evenly placed between the widgets.
+	SpaceBetween
+	// SpaceAround means the free space is evenly placed around the widgets,
+	// with half of the space between two widgets before the first one and after the last one.
+	SpaceAround
+	// SpaceEvenly means the free space is evenly placed between the widgets,
+	// before the first one and after the last one.
+	SpaceEvenly
 )
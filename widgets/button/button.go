@@ -2,7 +2,6 @@ package button
 
 import (
 	"github.com/mkch/goui"
-	"github.com/mkch/goui/native"
 )
 
 type Button struct {
@@ -17,7 +16,8 @@ func (btn *Button) WidgetID() goui.ID {
 }
 
 func (btn *Button) CreateElement(ctx *goui.Context) (goui.Element, error) {
-	handle, err := native.CreateButton(ctx.NativeWindow(), btn.Label)
+	backend := ctx.NativeBackend()
+	handle, err := backend.CreateButton(ctx.NativeWindow(), btn.Label)
 	if err != nil {
 		return nil, err
 	}
@@ -27,11 +27,12 @@ func (btn *Button) CreateElement(ctx *goui.Context) (goui.Element, error) {
 			ElementBase: goui.ElementBase{
 				ElementLayouter: layouter,
 			},
+			Backend:     backend,
 			Handle:      handle,
-			DestroyFunc: native.DestroyWindow,
+			DestroyFunc: backend.DestroyWindow,
 		},
 	}
-	native.SetButtonOnClickListener(handle, func() {
+	backend.SetButtonOnClickListener(handle, func() {
 		if btn.OnClick != nil {
 			btn.OnClick(ctx)
 		}
@@ -48,11 +49,11 @@ func (e *buttonElement) SetWidget(ctx *goui.Context, widget goui.Widget) {
 	if oldWidget := e.Widget(); oldWidget != nil {
 		oldBtn := oldWidget.(*Button)
 		if oldBtn.Label != newBtn.Label {
-			native.SetButtonLabel(e.Handle, newBtn.Label)
+			e.Backend.SetButtonLabel(e.Handle, newBtn.Label)
 		}
 	}
 	// func type are not comparable, so we always reset the OnClick listener.
-	native.SetButtonOnClickListener(e.Handle, func() {
+	e.Backend.SetButtonOnClickListener(e.Handle, func() {
 		if newBtn.OnClick != nil {
 			newBtn.OnClick(ctx)
 		}
@@ -69,7 +70,6 @@ type buttonLayouter struct {
 var defaultButtonPadding = goui.Size{Width: 15, Height: 10}
 
 func (l *buttonLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
-	elem := l.Element().(*buttonElement)
 	if constraints.TightWidth() && constraints.TightHeight() {
 		size = goui.Size{
 			Width:  constraints.MinWidth,
@@ -78,20 +78,65 @@ func (l *buttonLayouter) Layout(ctx *goui.Context, constraints goui.Constraints)
 		l.layoutSize = size
 		return
 	}
+	if size, err = l.intrinsicSize(); err != nil {
+		return
+	}
+	size = constraints.Clamp(size)
+	l.layoutSize = size
+	return
+}
+
+// intrinsicSize returns the minimum size of the button plus the padding.
+// The label is single-line, so the intrinsic dimensions don't depend on each other.
+func (l *buttonLayouter) intrinsicSize() (size goui.Size, err error) {
+	elem := l.Element().(*buttonElement)
 	widget := elem.Widget().(*Button)
 	padding := widget.Padding
 	if padding == nil {
 		padding = &defaultButtonPadding
 	}
-	intrinsicWidth, intrinsicHeight, err := native.GetButtonMinimumSize(elem.Handle, widget.Label)
+	intrinsicWidth, intrinsicHeight, err := elem.Backend.GetButtonMinimumSize(elem.Handle, widget.Label)
 	if err != nil {
 		return
 	}
-	size = constraints.Clamp(goui.Size{Width: intrinsicWidth + padding.Width, Height: intrinsicHeight + padding.Height})
-	l.layoutSize = size
-	return
+	return goui.Size{Width: intrinsicWidth + padding.Width, Height: intrinsicHeight + padding.Height}, nil
+}
+
+func (l *buttonLayouter) MinIntrinsicWidth(height int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Width, err
+}
+
+func (l *buttonLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Width, err
+}
+
+func (l *buttonLayouter) MinIntrinsicHeight(width int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Height, err
+}
+
+func (l *buttonLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Height, err
+}
+
+// Baseline returns the baseline of the label, where the backend draws it in the button.
+func (l *buttonLayouter) Baseline() (baseline int, ok bool, err error) {
+	elem := l.Element().(*buttonElement)
+	ascent, _, err := elem.Backend.GetTextMetrics(elem.Handle)
+	if err != nil {
+		return
+	}
+	top, err := elem.Backend.GetTextTop(elem.Handle, l.layoutSize.Height)
+	if err != nil {
+		return
+	}
+	return top + ascent, true, nil
 }
 
 func (l *buttonLayouter) PositionAt(x, y int) (err error) {
-	return native.SetWidgetDimensions(l.Element().(*buttonElement).Handle, x, y, l.layoutSize.Width, l.layoutSize.Height)
+	elem := l.Element().(*buttonElement)
+	return elem.Backend.SetWidgetDimensions(elem.Handle, x, y, l.layoutSize.Width, l.layoutSize.Height)
 }
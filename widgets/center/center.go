@@ -49,6 +49,8 @@ func (c *Center) Child(n int) goui.Widget {
 
 func (c *Center) Exclusive(goui.Container) { /*Nop*/ }
 
+func (c *Center) SingleChild() { /*Nop*/ }
+
 type centerElement struct {
 	goui.ElementBase
 }
@@ -66,14 +68,10 @@ func (e *centerElement) SetWidget(ctx *goui.Context, widget goui.Widget) {
 
 type centerLayouter struct {
 	goui.LayouterBase
-	lastConstraints *goui.Constraints // For replaying
-	childOffset     goui.Point
-	pos             goui.Point
+	childOffset goui.Point
 }
 
 func (l *centerLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
-	l.lastConstraints = &constraints
-
 	for child := range l.Children() {
 		var childSize goui.Size
 		childSize, err = child.Layout(ctx, constraints)
@@ -105,7 +103,6 @@ func (l *centerLayouter) Layout(ctx *goui.Context, constraints goui.Constraints)
 }
 
 func (l *centerLayouter) PositionAt(x, y int) (err error) {
-	l.pos = goui.Point{X: x, Y: y}
 	children := slices.Collect(l.Children())
 	if children == nil {
 		return nil
@@ -113,20 +110,15 @@ func (l *centerLayouter) PositionAt(x, y int) (err error) {
 	return children[0].PositionAt(x+l.childOffset.X, y+l.childOffset.Y)
 }
 
-func (l *centerLayouter) Replayer() func(ctx *goui.Context) error {
-	if l.lastConstraints == nil {
-		// No previous layout info.
-		return nil
+func (l *centerLayouter) Baseline() (baseline int, ok bool, err error) {
+	if baseline, ok, err = l.LayouterBase.Baseline(); !ok {
+		return
 	}
+	return baseline + l.childOffset.Y, true, nil
+}
+
+func (l *centerLayouter) FixedSize() bool {
 	center := l.Element().(*centerElement).Widget().(*Center)
-	if center.WidthFactor != 0 || center.HeightFactor != 0 {
-		// Cannot replay if size depends on child size.
-		return nil
-	}
-	return func(ctx *goui.Context) error {
-		if _, err := l.Layout(ctx, *l.lastConstraints); err != nil {
-			return err
-		}
-		return l.PositionAt(l.pos.X, l.pos.Y)
-	}
+	// The size depends on the child size if scaled.
+	return center.WidthFactor == 0 && center.HeightFactor == 0
 }
@@ -0,0 +1,26 @@
+package center
+
+import (
+	"testing"
+
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/widgets/button"
+	"github.com/mkch/goui/widgets/widgetstest"
+)
+
+func TestCenter_Golden(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &Center{
+		Widget: &button.Button{Label: "Centered"},
+	}, 200, 100)
+	wt.ExpectGolden("center", nil)
+
+	wt = widgetstest.NewWidgetTester(t, &Center{
+		WidthFactor:  200,
+		HeightFactor: 150,
+		Widget:       &button.Button{ID: goui.ValueID("button"), Label: "Factor"},
+	}, 200, 100)
+	if rect := wt.Rect(wt.Find(widgetstest.ByType[*Center]())); rect.Left != 0 || rect.Top != 0 {
+		t.Fatalf("unexpected Center bounds %v", rect)
+	}
+	wt.ExpectGolden("center_factor", nil)
+}
@@ -9,13 +9,18 @@ import (
 // Column is a [Container] [Widget] that arranges its children in a vertical column.
 // The width of Column is the maximum width of its children.
 // The height of Column is calculated based on its MainAxisSize property:
-// - If MainAxisSize is Min, the height of Column is the sum of heights of its children.
+// - If MainAxisSize is Min, the height of Column is the sum of heights of its children and the spacing between them.
 // - If MainAxisSize is Max, the height of Column is the maximum height allowed by its parent.
+//
+// The free space in the main axis, if any, is placed according to MainAxisAlignment.
 type Column struct {
 	ID                 goui.ID
 	Widgets            []goui.Widget
 	MainAxisSize       axes.Size
+	MainAxisAlignment  axes.MainAxisAlignment
 	CrossAxisAlignment axes.Alignment
+	Spacing            int  // The gap between adjacent children.
+	Reverse            bool // Whether the children are placed from bottom to top.
 }
 
 func (c *Column) WidgetID() goui.ID {
@@ -23,18 +28,22 @@ func (c *Column) WidgetID() goui.ID {
 }
 
 func (c *Column) CreateElement(ctx *goui.Context) (goui.Element, error) {
-	return &goui.ElementBase{
-		ElementLayouter: &rowcol.Layouter{
-			Main:               func(s *goui.Size) *int { return &s.Height },
-			Cross:              func(s *goui.Size) *int { return &s.Width },
-			MaxMain:            func(c *goui.Constraints) *int { return &c.MaxHeight },
-			MinMain:            func(c *goui.Constraints) *int { return &c.MinHeight },
-			MaxCross:           func(c *goui.Constraints) *int { return &c.MaxWidth },
-			MinCross:           func(c *goui.Constraints) *int { return &c.MinWidth },
-			MainAxisSize:       func() axes.Size { return c.MainAxisSize },
-			CrossAxisAlignment: func() axes.Alignment { return c.CrossAxisAlignment },
-		},
-	}, nil
+	layouter := &rowcol.Layouter{
+		Main:     func(s *goui.Size) *int { return &s.Height },
+		Cross:    func(s *goui.Size) *int { return &s.Width },
+		MaxMain:  func(c *goui.Constraints) *int { return &c.MaxHeight },
+		MinMain:  func(c *goui.Constraints) *int { return &c.MinHeight },
+		MaxCross: func(c *goui.Constraints) *int { return &c.MaxWidth },
+		MinCross: func(c *goui.Constraints) *int { return &c.MinWidth },
+	}
+	// The properties are read from the current widget of the element, which is replaced on rebuild.
+	widget := func() *Column { return layouter.Element().Widget().(*Column) }
+	layouter.MainAxisSize = func() axes.Size { return widget().MainAxisSize }
+	layouter.MainAxisAlignment = func() axes.MainAxisAlignment { return widget().MainAxisAlignment }
+	layouter.CrossAxisAlignment = func() axes.Alignment { return widget().CrossAxisAlignment }
+	layouter.Spacing = func() int { return widget().Spacing }
+	layouter.Reverse = func() bool { return widget().Reverse }
+	return &goui.ElementBase{ElementLayouter: layouter}, nil
 }
 
 func (c *Column) NumChildren() int {
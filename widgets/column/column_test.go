@@ -29,9 +29,11 @@ type mockLayouter struct {
 	goui.LayouterBase
 	IntrinsicSize goui.Size
 	Position      goui.Point
+	Constraints   goui.Constraints // The constraints of the last layout.
 }
 
 func (l *mockLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
+	l.Constraints = constraints
 	return constraints.Clamp(l.IntrinsicSize), nil
 }
 
@@ -40,6 +42,22 @@ func (l *mockLayouter) PositionAt(x, y int) error {
 	return nil
 }
 
+func (l *mockLayouter) MinIntrinsicWidth(height int) (int, error) {
+	return l.IntrinsicSize.Width, nil
+}
+
+func (l *mockLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	return l.IntrinsicSize.Width, nil
+}
+
+func (l *mockLayouter) MinIntrinsicHeight(width int) (int, error) {
+	return l.IntrinsicSize.Height, nil
+}
+
+func (l *mockLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	return l.IntrinsicSize.Height, nil
+}
+
 func Test_ColumnSize(t *testing.T) {
 	ctx := widgetstest.NewContext()
 	widget1 := &mockWidget{
@@ -204,3 +222,182 @@ func Test_ColumnAlign(t *testing.T) {
 		t.Fatalf("Unexpected widget2 X position: got %d, want 0", x)
 	}
 }
+
+func Test_ColumnIntrinsic(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, size goui.Size) goui.Widget {
+		return &mockWidget{
+			ID:      goui.ValueID(id),
+			Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: size}}},
+		}
+	}
+	_, layouter, err := widgetstest.BuildElementTree(ctx, &Column{
+		Widgets: []goui.Widget{
+			newWidget("widget1", goui.Size{Width: 100, Height: 50}),
+			newWidget("widget2", goui.Size{Width: 200, Height: 30}),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	for _, test := range []struct {
+		name      string
+		intrinsic func(int) (int, error)
+		want      int
+	}{
+		{"MinIntrinsicHeight", layouter.MinIntrinsicHeight, 80},
+		{"MaxIntrinsicHeight", layouter.MaxIntrinsicHeight, 80},
+		{"MinIntrinsicWidth", layouter.MinIntrinsicWidth, 200},
+		{"MaxIntrinsicWidth", layouter.MaxIntrinsicWidth, 200},
+	} {
+		got, err := test.intrinsic(goui.Infinity)
+		if err != nil {
+			t.Fatalf("%v error: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Fatalf("Unexpected %v: got %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func Test_ColumnMainAxisAlignment(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, size goui.Size) *mockWidget {
+		return &mockWidget{
+			ID:      goui.ValueID(id),
+			Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: size}}},
+		}
+	}
+	widgets := []*mockWidget{
+		newWidget("widget1", goui.Size{Width: 50, Height: 100}),
+		newWidget("widget2", goui.Size{Width: 30, Height: 50}),
+	}
+	for _, test := range []struct {
+		alignment axes.MainAxisAlignment
+		spacing   int
+		reverse   bool
+		y         []int
+	}{
+		{axes.MainAxisStart, 0, false, []int{0, 100}},
+		{axes.MainAxisEnd, 0, false, []int{150, 250}},
+		{axes.MainAxisCenter, 0, false, []int{75, 175}},
+		{axes.SpaceBetween, 0, false, []int{0, 250}},
+		{axes.SpaceAround, 0, false, []int{37, 212}},
+		{axes.SpaceEvenly, 0, false, []int{50, 200}},
+		{axes.SpaceEvenly, 10, false, []int{46, 202}},
+		{axes.MainAxisStart, 10, true, []int{200, 140}},
+		{axes.MainAxisEnd, 10, true, []int{60, 0}},
+		{axes.MainAxisCenter, 10, true, []int{130, 70}},
+	} {
+		_, layouter, err := widgetstest.BuildElementTree(ctx, &Column{
+			Widgets:           []goui.Widget{widgets[0], widgets[1]},
+			MainAxisAlignment: test.alignment,
+			Spacing:           test.spacing,
+			Reverse:           test.reverse,
+		}, nil)
+		if err != nil {
+			t.Fatalf("BuildElementTree error: %v", err)
+		}
+		size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 200, MaxHeight: 300})
+		if err != nil {
+			t.Fatalf("Layout error: %v", err)
+		}
+		if size.Width != 50 || size.Height != 300 {
+			t.Fatalf("Unexpected size: got %v, want Width=50 Height=300", size)
+		}
+		if err = layouter.PositionAt(0, 0); err != nil {
+			t.Fatalf("PositionAt error: %v", err)
+		}
+		for i, widget := range widgets {
+			if y := widget.Element.ElementLayouter.(*mockLayouter).Position.Y; y != test.y[i] {
+				t.Fatalf("Unexpected widget%d Y position with alignment %v, spacing %v and reverse %v: got %d, want %d",
+					i+1, test.alignment, test.spacing, test.reverse, y, test.y[i])
+			}
+		}
+	}
+}
+
+func Test_ColumnSpacingStretch(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	widget1 := &mockWidget{
+		ID:      goui.ValueID("widget1"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 50, Height: 100}}}},
+	}
+	widget2 := &mockWidget{
+		ID:      goui.ValueID("widget2"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 30, Height: 50}}}},
+	}
+	_, layouter, err := widgetstest.BuildElementTree(ctx, &Column{
+		Widgets:            []goui.Widget{widget1, widget2},
+		MainAxisSize:       axes.Min,
+		CrossAxisAlignment: axes.Stretch,
+		Spacing:            20,
+	}, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	if height, err := layouter.MinIntrinsicHeight(goui.Infinity); err != nil {
+		t.Fatalf("MinIntrinsicHeight error: %v", err)
+	} else if height != 170 {
+		t.Fatalf("Unexpected MinIntrinsicHeight: got %d, want 170", height)
+	}
+	size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 200, MaxHeight: 300})
+	if err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	if size.Width != 200 || size.Height != 170 {
+		t.Fatalf("Unexpected size: got %v, want Width=200 Height=170", size)
+	}
+	for i, widget := range []*mockWidget{widget1, widget2} {
+		if c := widget.Element.ElementLayouter.(*mockLayouter).Constraints; c.MinWidth != 200 || c.MaxWidth != 200 {
+			t.Fatalf("Unexpected widget%d constraints: got %v, want MinWidth=200 MaxWidth=200", i+1, c)
+		}
+	}
+	if err = layouter.PositionAt(0, 0); err != nil {
+		t.Fatalf("PositionAt error: %v", err)
+	}
+	if y := widget2.Element.ElementLayouter.(*mockLayouter).Position.Y; y != 120 {
+		t.Fatalf("Unexpected widget2 Y position: got %d, want 120", y)
+	}
+}
+
+func Test_ColumnFragmentBuilt(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, size goui.Size) *mockWidget {
+		return &mockWidget{
+			ID:      goui.ValueID(id),
+			Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: size}}},
+		}
+	}
+	widgets := []*mockWidget{
+		newWidget("widget1", goui.Size{Width: 50, Height: 100}),
+		newWidget("widget2", goui.Size{Width: 30, Height: 50}),
+		newWidget("widget3", goui.Size{Width: 40, Height: 20}),
+	}
+	// A helper widget adding two rows to the column.
+	rows := goui.NewStatelessWidget(goui.ValueID("rows"), func(ctx *goui.Context) goui.Widget {
+		return &goui.Fragment{Widgets: []goui.Widget{widgets[1], widgets[2]}}
+	})
+	_, layouter, err := widgetstest.BuildElementTree(ctx, &Column{
+		Widgets:      []goui.Widget{widgets[0], rows},
+		MainAxisSize: axes.Min,
+	}, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 200, MaxHeight: 300})
+	if err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	if size.Width != 50 || size.Height != 170 {
+		t.Fatalf("Unexpected size: got %v, want Width=50 Height=170", size)
+	}
+	if err = layouter.PositionAt(0, 0); err != nil {
+		t.Fatalf("PositionAt error: %v", err)
+	}
+	for i, y := range []int{0, 100, 150} {
+		if got := widgets[i].Element.ElementLayouter.(*mockLayouter).Position.Y; got != y {
+			t.Fatalf("Unexpected widget%d Y position: got %d, want %d", i+1, got, y)
+		}
+	}
+}
@@ -36,6 +36,8 @@ func (p *Expanded) Child(n int) goui.Widget {
 
 func (p *Expanded) Exclusive(goui.Container) { /*Nop*/ }
 
+func (p *Expanded) SingleChild() { /*Nop*/ }
+
 type expandedLayouter struct {
 	goui.LayouterBase
 }
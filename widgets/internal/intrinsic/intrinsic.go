@@ -0,0 +1,69 @@
+// Package intrinsic provides utilities to implement IntrinsicWidth and IntrinsicHeight widgets.
+package intrinsic
+
+import (
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/internal/debug"
+)
+
+// Layouter is a layouter for IntrinsicWidth and IntrinsicHeight widgets, which sizes
+// its only child to the max intrinsic size of the child in one axis.
+type Layouter struct {
+	goui.LayouterBase
+
+	// Horizontal specifies whether the sized axis is horizontal, which is true for IntrinsicWidth.
+	Horizontal bool
+}
+
+func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
+	for child := range l.Children() {
+		childConstraints := constraints
+		if l.Horizontal && !constraints.TightWidth() {
+			var width int
+			if width, err = child.MaxIntrinsicWidth(constraints.MaxHeight); err != nil {
+				return
+			}
+			width = constraints.ClampWidth(width)
+			childConstraints.MinWidth = width
+			childConstraints.MaxWidth = width
+		} else if !l.Horizontal && !constraints.TightHeight() {
+			var height int
+			if height, err = child.MaxIntrinsicHeight(constraints.MaxWidth); err != nil {
+				return
+			}
+			height = constraints.ClampHeight(height)
+			childConstraints.MinHeight = height
+			childConstraints.MaxHeight = height
+		}
+		size, err = child.Layout(ctx, childConstraints)
+		if err != nil {
+			return
+		}
+		err = debug.CheckLayoutOverflow(ctx, child.Element().Widget(), size, childConstraints)
+		return // Only one child
+	}
+	return constraints.MinSize(), nil
+}
+
+func (l *Layouter) PositionAt(x, y int) (err error) {
+	for child := range l.Children() {
+		return child.PositionAt(x, y)
+	}
+	return
+}
+
+func (l *Layouter) MinIntrinsicWidth(height int) (int, error) {
+	if l.Horizontal {
+		// The child is never narrower than its max intrinsic width.
+		return l.MaxIntrinsicWidth(height)
+	}
+	return l.LayouterBase.MinIntrinsicWidth(height)
+}
+
+func (l *Layouter) MinIntrinsicHeight(width int) (int, error) {
+	if !l.Horizontal {
+		// The child is never shorter than its max intrinsic height.
+		return l.MaxIntrinsicHeight(width)
+	}
+	return l.LayouterBase.MinIntrinsicHeight(width)
+}
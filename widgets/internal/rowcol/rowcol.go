@@ -14,6 +14,9 @@ type Layouter struct {
 	goui.LayouterBase
 	childrenOffsets []goui.Size
 
+	// Horizontal specifies whether the main axis is horizontal, which is true for [Row].
+	Horizontal bool
+
 	// Main returns the main axis value (Width for [Row], Height for [Column]) of the given [Size].
 	Main func(*goui.Size) *int
 	// Cross returns the cross axis value (Height for [Row], Width for [Column]) of the given [Size].
@@ -28,7 +31,12 @@ type Layouter struct {
 	MinCross func(*goui.Constraints) *int
 
 	MainAxisSize       func() axes.Size
+	MainAxisAlignment  func() axes.MainAxisAlignment
 	CrossAxisAlignment func() axes.Alignment
+	// Spacing returns the gap between adjacent children in the main axis.
+	Spacing func() int
+	// Reverse returns whether the children are placed from the end to the start of the main axis.
+	Reverse func() bool
 }
 
 func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
@@ -36,6 +44,18 @@ func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size
 	var notExpandableChildrenMain = 0
 	*l.Cross(&size) = *l.MinCross(&constraints)
 	var childrenSizes []goui.Size
+	// The children are counted beforehand to reserve the spacing between them.
+	var numChildren = 0
+	for range l.Children() {
+		numChildren++
+	}
+	var spacing = l.Spacing() * max(numChildren-1, 0)
+	notExpandableChildrenMain += spacing
+	// The cross axis constraints of the children are tight when stretched in a bounded cross axis.
+	var minCross = 0
+	if l.CrossAxisAlignment() == axes.Stretch && *l.MaxCross(&constraints) != goui.Infinity {
+		minCross = *l.MaxCross(&constraints)
+	}
 	var expandedChildren []goui.Layouter
 	var expandedChildrenIndexes []int
 	for child := range l.Children() {
@@ -47,6 +67,7 @@ func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size
 			continue
 		}
 		var childConstraints goui.Constraints
+		*l.MinCross(&childConstraints) = minCross
 		*l.MaxCross(&childConstraints) = *l.MaxCross(&constraints)
 		*l.MaxMain(&childConstraints) = gg.IfFunc(*l.MaxMain(&constraints) == goui.Infinity,
 			func() int { return goui.Infinity },
@@ -71,7 +92,7 @@ func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size
 		sizes, err = expanded.Layout(ctx, availableSpace, expandedChildren, func(c *goui.Constraints, mainAxis int) {
 			*l.MinMain(c) = mainAxis
 			*l.MaxMain(c) = mainAxis
-			*l.MinCross(c) = 0
+			*l.MinCross(c) = minCross
 			*l.MaxCross(c) = *l.MaxCross(&constraints)
 		})
 		if err != nil {
@@ -95,24 +116,31 @@ func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size
 		}
 	}
 	*l.Cross(&size) = max(*l.Cross(&size), *l.MinCross(&constraints))
-	// calculate children offsets
-	var childMain = 0
+	// determine main axis size
+	var childrenMain = spacing
 	for _, childSize := range childrenSizes {
-		var offset goui.Size
-		*l.Main(&offset) = childMain
-		l.childrenOffsets = append(l.childrenOffsets, offset)
-		childMain += *l.Main(&childSize)
+		childrenMain += *l.Main(&childSize)
 	}
-	// determine main axis size
 	switch l.MainAxisSize() {
 	case axes.Min:
-		*l.Main(&size) = max(childMain, *l.MinMain(&constraints))
+		*l.Main(&size) = max(childrenMain, *l.MinMain(&constraints))
 	case axes.Max:
 		*l.Main(&size) = *l.MaxMain(&constraints)
 	}
+	// calculate children offsets with main axis alignment
+	childMain, gap := l.distribute(max(*l.Main(&size)-childrenMain, 0), len(childrenSizes))
+	for _, childSize := range childrenSizes {
+		var offset goui.Size
+		*l.Main(&offset) = childMain
+		if l.Reverse() {
+			*l.Main(&offset) = *l.Main(&size) - childMain - *l.Main(&childSize)
+		}
+		l.childrenOffsets = append(l.childrenOffsets, offset)
+		childMain += *l.Main(&childSize) + gap
+	}
 	// apply cross axis alignment
 	switch l.CrossAxisAlignment() {
-	case axes.Start:
+	case axes.Start, axes.Stretch:
 		// do nothing
 	case axes.Center:
 		for i := range l.childrenOffsets {
@@ -122,6 +150,78 @@ func (l *Layouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size
 		for i := range l.childrenOffsets {
 			*l.Cross(&l.childrenOffsets[i]) = *l.Cross(&size) - *l.Cross(&childrenSizes[i])
 		}
+	case axes.Baseline:
+		if l.Horizontal {
+			err = l.alignBaselines(&size, &constraints, childrenSizes)
+		}
+	}
+	return
+}
+
+// distribute returns the offset of the first child and the gap between adjacent children
+// in the main axis, placing the free space according to the main axis alignment.
+func (l *Layouter) distribute(free, n int) (leading, gap int) {
+	gap = l.Spacing()
+	switch l.MainAxisAlignment() {
+	case axes.MainAxisCenter:
+		leading = free / 2
+	case axes.MainAxisEnd:
+		leading = free
+	case axes.SpaceBetween:
+		if n > 1 {
+			gap += free / (n - 1)
+		}
+	case axes.SpaceAround:
+		if n > 0 {
+			leading = free / n / 2
+			gap += free / n
+		}
+	case axes.SpaceEvenly:
+		leading = free / (n + 1)
+		gap += leading
+	}
+	return
+}
+
+// alignBaselines aligns the baselines of the children in a row, and grows the row
+// to fit them. The children without baselines are aligned to the top.
+func (l *Layouter) alignBaselines(size *goui.Size, constraints *goui.Constraints, childrenSizes []goui.Size) error {
+	baselines := make([]int, len(childrenSizes))
+	aligned := make([]bool, len(childrenSizes)) // Whether the child has a baseline.
+	var above, below int                        // The max extents above and below the baseline.
+	i := 0
+	for child := range l.Children() {
+		baseline, ok, err := child.Baseline()
+		if err != nil {
+			return err
+		}
+		if ok {
+			baselines[i], aligned[i] = baseline, true
+			above = max(above, baseline)
+			below = max(below, childrenSizes[i].Height-baseline)
+		}
+		i++
+	}
+	size.Height = max(size.Height, constraints.ClampHeight(above+below))
+	for i, baseline := range baselines {
+		if aligned[i] {
+			l.childrenOffsets[i].Height = above - baseline
+		}
+	}
+	return nil
+}
+
+// Baseline returns the baseline of the first child with a baseline.
+func (l *Layouter) Baseline() (baseline int, ok bool, err error) {
+	i := 0
+	for child := range l.Children() {
+		if baseline, ok, err = child.Baseline(); err != nil {
+			return
+		}
+		if ok {
+			return baseline + l.childrenOffsets[i].Height, true, nil
+		}
+		i++
 	}
 	return
 }
@@ -136,3 +236,60 @@ func (l *Layouter) PositionAt(x, y int) (err error) {
 	}
 	return nil
 }
+
+// The intrinsic main axis size is the sum of the ones of the children and the spacing,
+// and the intrinsic cross axis size is the max of the ones of the children, which are
+// queried with the main axis unbounded.
+
+func (l *Layouter) MinIntrinsicWidth(height int) (int, error) {
+	if l.Horizontal {
+		return l.sumIntrinsic(func(child goui.Layouter) (int, error) { return child.MinIntrinsicWidth(height) })
+	}
+	return l.maxIntrinsic(func(child goui.Layouter) (int, error) { return child.MinIntrinsicWidth(goui.Infinity) })
+}
+
+func (l *Layouter) MaxIntrinsicWidth(height int) (int, error) {
+	if l.Horizontal {
+		return l.sumIntrinsic(func(child goui.Layouter) (int, error) { return child.MaxIntrinsicWidth(height) })
+	}
+	return l.maxIntrinsic(func(child goui.Layouter) (int, error) { return child.MaxIntrinsicWidth(goui.Infinity) })
+}
+
+func (l *Layouter) MinIntrinsicHeight(width int) (int, error) {
+	if !l.Horizontal {
+		return l.sumIntrinsic(func(child goui.Layouter) (int, error) { return child.MinIntrinsicHeight(width) })
+	}
+	return l.maxIntrinsic(func(child goui.Layouter) (int, error) { return child.MinIntrinsicHeight(goui.Infinity) })
+}
+
+func (l *Layouter) MaxIntrinsicHeight(width int) (int, error) {
+	if !l.Horizontal {
+		return l.sumIntrinsic(func(child goui.Layouter) (int, error) { return child.MaxIntrinsicHeight(width) })
+	}
+	return l.maxIntrinsic(func(child goui.Layouter) (int, error) { return child.MaxIntrinsicHeight(goui.Infinity) })
+}
+
+// sumIntrinsic returns the sum of the intrinsic dimensions of the children returned by f,
+// and the spacing between them.
+func (l *Layouter) sumIntrinsic(f func(child goui.Layouter) (int, error)) (sum int, err error) {
+	for child := range l.Children() {
+		var value int
+		if value, err = f(child); err != nil {
+			return
+		}
+		sum += value + l.Spacing()
+	}
+	return max(sum-l.Spacing(), 0), nil
+}
+
+// maxIntrinsic returns the max of the intrinsic dimensions of the children returned by f.
+func (l *Layouter) maxIntrinsic(f func(child goui.Layouter) (int, error)) (result int, err error) {
+	for child := range l.Children() {
+		var value int
+		if value, err = f(child); err != nil {
+			return
+		}
+		result = max(result, value)
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package intrinsicheight
+
+import (
+	"github.com/mkch/gg"
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/widgets/internal/intrinsic"
+)
+
+// IntrinsicHeight is a [Container] [Widget] that sizes its child to the max intrinsic
+// height of the child, such as sizing a row of buttons to the tallest one.
+// The height is still clamped by the constraints from the parent.
+type IntrinsicHeight struct {
+	ID     goui.ID
+	Widget goui.Widget
+}
+
+func (w *IntrinsicHeight) WidgetID() goui.ID {
+	return w.ID
+}
+
+func (w *IntrinsicHeight) CreateElement(ctx *goui.Context) (goui.Element, error) {
+	return &goui.ElementBase{
+		ElementLayouter: &intrinsic.Layouter{Horizontal: false},
+	}, nil
+}
+
+func (w *IntrinsicHeight) NumChildren() int {
+	return gg.If(w.Widget != nil, 1, 0)
+}
+
+func (w *IntrinsicHeight) Child(n int) goui.Widget {
+	return w.Widget
+}
+
+func (w *IntrinsicHeight) Exclusive(goui.Container) { /*Nop*/ }
+
+func (w *IntrinsicHeight) SingleChild() { /*Nop*/ }
@@ -0,0 +1,103 @@
+package intrinsicheight
+
+import (
+	"testing"
+
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/native/headless"
+	"github.com/mkch/goui/native/software"
+	"github.com/mkch/goui/widgets/axes"
+	"github.com/mkch/goui/widgets/button"
+	"github.com/mkch/goui/widgets/label"
+	"github.com/mkch/goui/widgets/row"
+	"github.com/mkch/goui/widgets/widgetstest"
+)
+
+type mockWidget struct {
+	ID      goui.ID
+	Element goui.ElementBase
+}
+
+func (w *mockWidget) WidgetID() goui.ID {
+	return w.ID
+}
+
+func (w *mockWidget) CreateElement(ctx *goui.Context) (goui.Element, error) {
+	return &w.Element, nil
+}
+
+// mockLayouter expands to fill the max size.
+type mockLayouter struct {
+	goui.LayouterBase
+	IntrinsicSize goui.Size
+	Constraints   goui.Constraints // The constraints of the last layout.
+}
+
+func (l *mockLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
+	l.Constraints = constraints
+	return goui.Size{Width: constraints.MaxWidth, Height: constraints.MaxHeight}, nil
+}
+
+func (l *mockLayouter) PositionAt(x, y int) error {
+	return nil
+}
+
+func (l *mockLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	return l.IntrinsicSize.Height, nil
+}
+
+func TestIntrinsicHeight(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &IntrinsicHeight{
+		Widget: &row.Row{
+			MainAxisSize:       axes.Min,
+			CrossAxisAlignment: axes.Stretch,
+			Widgets: []goui.Widget{
+				&label.Label{Text: "Name"},
+				&button.Button{Label: "OK", Padding: &goui.Size{}},
+			},
+		},
+	}, 200, 100)
+	// The stretching row would fill the height of the window,
+	// but it is as high as the button, to which the label is stretched.
+	height := software.LineHeight + 2*headless.ButtonEdge
+	if rect := wt.Rect(wt.Find(widgetstest.ByType[*row.Row]())); rect.Height() != height {
+		t.Fatalf("unexpected row bounds %v, want height %d", rect, height)
+	}
+	if rect := wt.Rect(wt.Find(widgetstest.ByText("Name"))); rect.Height() != height {
+		t.Fatalf("unexpected label bounds %v, want height %d", rect, height)
+	}
+}
+
+func TestIntrinsicHeight_Constraints(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	for _, test := range []struct {
+		intrinsicHeight int
+		constraints     goui.Constraints
+		want            goui.Constraints
+	}{
+		// Loose height is tightened to the max intrinsic height.
+		{30, goui.Constraints{MaxWidth: 200, MaxHeight: 100}, goui.Constraints{MaxWidth: 200, MinHeight: 30, MaxHeight: 30}},
+		// The max intrinsic height is clamped.
+		{300, goui.Constraints{MaxWidth: 200, MinHeight: 10, MaxHeight: 100}, goui.Constraints{MaxWidth: 200, MinHeight: 100, MaxHeight: 100}},
+		// Tight height is unchanged.
+		{30, goui.Constraints{MaxWidth: 200, MinHeight: 50, MaxHeight: 50}, goui.Constraints{MaxWidth: 200, MinHeight: 50, MaxHeight: 50}},
+	} {
+		layouter := &mockLayouter{IntrinsicSize: goui.Size{Height: test.intrinsicHeight}}
+		_, root, err := widgetstest.BuildElementTree(ctx, &IntrinsicHeight{
+			Widget: &mockWidget{Element: goui.ElementBase{ElementLayouter: layouter}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("BuildElementTree error: %v", err)
+		}
+		size, err := root.Layout(ctx, test.constraints)
+		if err != nil {
+			t.Fatalf("Layout error: %v", err)
+		}
+		if layouter.Constraints != test.want {
+			t.Fatalf("unexpected child constraints in %v: got %v, want %v", test.constraints, layouter.Constraints, test.want)
+		}
+		if size.Height != test.want.MaxHeight {
+			t.Fatalf("unexpected size in %v: got %v, want height %d", test.constraints, size, test.want.MaxHeight)
+		}
+	}
+}
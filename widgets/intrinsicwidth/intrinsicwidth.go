@@ -0,0 +1,37 @@
+package intrinsicwidth
+
+import (
+	"github.com/mkch/gg"
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/widgets/internal/intrinsic"
+)
+
+// IntrinsicWidth is a [Container] [Widget] that sizes its child to the max intrinsic
+// width of the child, such as sizing a column of labels to the widest one.
+// The width is still clamped by the constraints from the parent.
+type IntrinsicWidth struct {
+	ID     goui.ID
+	Widget goui.Widget
+}
+
+func (w *IntrinsicWidth) WidgetID() goui.ID {
+	return w.ID
+}
+
+func (w *IntrinsicWidth) CreateElement(ctx *goui.Context) (goui.Element, error) {
+	return &goui.ElementBase{
+		ElementLayouter: &intrinsic.Layouter{Horizontal: true},
+	}, nil
+}
+
+func (w *IntrinsicWidth) NumChildren() int {
+	return gg.If(w.Widget != nil, 1, 0)
+}
+
+func (w *IntrinsicWidth) Child(n int) goui.Widget {
+	return w.Widget
+}
+
+func (w *IntrinsicWidth) Exclusive(goui.Container) { /*Nop*/ }
+
+func (w *IntrinsicWidth) SingleChild() { /*Nop*/ }
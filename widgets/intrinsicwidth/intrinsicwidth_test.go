@@ -0,0 +1,101 @@
+package intrinsicwidth
+
+import (
+	"testing"
+
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/native/software"
+	"github.com/mkch/goui/widgets/axes"
+	"github.com/mkch/goui/widgets/column"
+	"github.com/mkch/goui/widgets/label"
+	"github.com/mkch/goui/widgets/widgetstest"
+)
+
+type mockWidget struct {
+	ID      goui.ID
+	Element goui.ElementBase
+}
+
+func (w *mockWidget) WidgetID() goui.ID {
+	return w.ID
+}
+
+func (w *mockWidget) CreateElement(ctx *goui.Context) (goui.Element, error) {
+	return &w.Element, nil
+}
+
+// mockLayouter expands to fill the max size.
+type mockLayouter struct {
+	goui.LayouterBase
+	IntrinsicSize goui.Size
+	Constraints   goui.Constraints // The constraints of the last layout.
+}
+
+func (l *mockLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
+	l.Constraints = constraints
+	return goui.Size{Width: constraints.MaxWidth, Height: constraints.MaxHeight}, nil
+}
+
+func (l *mockLayouter) PositionAt(x, y int) error {
+	return nil
+}
+
+func (l *mockLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	return l.IntrinsicSize.Width, nil
+}
+
+func TestIntrinsicWidth(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &IntrinsicWidth{
+		Widget: &column.Column{
+			MainAxisSize:       axes.Min,
+			CrossAxisAlignment: axes.Stretch,
+			Widgets: []goui.Widget{
+				&label.Label{Text: "Name"},
+				&label.Label{Text: "Address"},
+			},
+		},
+	}, 200, 100)
+	// The stretching column would fill the width of the window,
+	// but it is as wide as the widest label, to which the labels are stretched.
+	width, _ := software.MeasureText("Address", false)
+	if rect := wt.Rect(wt.Find(widgetstest.ByType[*column.Column]())); rect.Width() != width {
+		t.Fatalf("unexpected column bounds %v, want width %d", rect, width)
+	}
+	if rect := wt.Rect(wt.Find(widgetstest.ByText("Name"))); rect.Width() != width {
+		t.Fatalf("unexpected label bounds %v, want width %d", rect, width)
+	}
+}
+
+func TestIntrinsicWidth_Constraints(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	for _, test := range []struct {
+		intrinsicWidth int
+		constraints    goui.Constraints
+		want           goui.Constraints
+	}{
+		// Loose width is tightened to the max intrinsic width.
+		{60, goui.Constraints{MaxWidth: 200, MaxHeight: 100}, goui.Constraints{MinWidth: 60, MaxWidth: 60, MaxHeight: 100}},
+		// The max intrinsic width is clamped.
+		{300, goui.Constraints{MinWidth: 10, MaxWidth: 200, MaxHeight: 100}, goui.Constraints{MinWidth: 200, MaxWidth: 200, MaxHeight: 100}},
+		// Tight width is unchanged.
+		{60, goui.Constraints{MinWidth: 150, MaxWidth: 150, MaxHeight: 100}, goui.Constraints{MinWidth: 150, MaxWidth: 150, MaxHeight: 100}},
+	} {
+		layouter := &mockLayouter{IntrinsicSize: goui.Size{Width: test.intrinsicWidth}}
+		_, root, err := widgetstest.BuildElementTree(ctx, &IntrinsicWidth{
+			Widget: &mockWidget{Element: goui.ElementBase{ElementLayouter: layouter}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("BuildElementTree error: %v", err)
+		}
+		size, err := root.Layout(ctx, test.constraints)
+		if err != nil {
+			t.Fatalf("Layout error: %v", err)
+		}
+		if layouter.Constraints != test.want {
+			t.Fatalf("unexpected child constraints in %v: got %v, want %v", test.constraints, layouter.Constraints, test.want)
+		}
+		if size.Width != test.want.MaxWidth {
+			t.Fatalf("unexpected size in %v: got %v, want width %d", test.constraints, size, test.want.MaxWidth)
+		}
+	}
+}
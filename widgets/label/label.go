@@ -1,9 +1,7 @@
 package label
 
 import (
-	"github.com/mkch/gg/errortrace"
 	"github.com/mkch/goui"
-	"github.com/mkch/goui/native"
 )
 
 type Label struct {
@@ -17,7 +15,8 @@ func (btn *Label) WidgetID() goui.ID {
 }
 
 func (btn *Label) CreateElement(ctx *goui.Context) (goui.Element, error) {
-	handle, err := native.CreateLabel(ctx.NativeWindow(), btn.Text)
+	backend := ctx.NativeBackend()
+	handle, err := backend.CreateLabel(ctx.NativeWindow(), btn.Text)
 	if err != nil {
 		return nil, err
 	}
@@ -27,8 +26,9 @@ func (btn *Label) CreateElement(ctx *goui.Context) (goui.Element, error) {
 			ElementBase: goui.ElementBase{
 				ElementLayouter: layouter,
 			},
+			Backend:     backend,
 			Handle:      handle,
-			DestroyFunc: native.DestroyWindow,
+			DestroyFunc: backend.DestroyWindow,
 		},
 	}
 	return elem, nil
@@ -43,8 +43,8 @@ func (e *labelElement) SetWidget(ctx *goui.Context, widget goui.Widget) {
 	if oldWidget := e.Widget(); oldWidget != nil {
 		oldLabel := oldWidget.(*Label)
 		if oldLabel.Text != newLabel.Text {
-			if err := native.SetLabelText(e.Handle, newLabel.Text); err != nil {
-				errortrace.Panic(err)
+			if err := e.Backend.SetLabelText(e.Handle, newLabel.Text); err != nil {
+				ctx.ReportError(goui.PhaseNative, e, err)
 			}
 		}
 	}
@@ -57,7 +57,6 @@ type labelLayouter struct {
 }
 
 func (l *labelLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
-	elem := l.Element().(*labelElement)
 	if constraints.TightWidth() && constraints.TightHeight() {
 		size = goui.Size{
 			Width:  constraints.MinWidth,
@@ -66,20 +65,60 @@ func (l *labelLayouter) Layout(ctx *goui.Context, constraints goui.Constraints)
 		l.layoutSize = size
 		return
 	}
+	if size, err = l.intrinsicSize(); err != nil {
+		return
+	}
+	size = constraints.Clamp(size)
+	l.layoutSize = size
+	return
+}
+
+// intrinsicSize returns the size of the text plus the padding.
+// The text is single-line, so the intrinsic dimensions don't depend on each other.
+func (l *labelLayouter) intrinsicSize() (size goui.Size, err error) {
+	elem := l.Element().(*labelElement)
 	widget := elem.Widget().(*Label)
 	padding := widget.Padding
 	if padding == nil {
 		padding = &goui.Size{Width: 0, Height: 0}
 	}
-	intrinsicWidth, intrinsicHeight, err := native.GetTextDrawingSize(elem.Handle, widget.Text, false)
+	intrinsicWidth, intrinsicHeight, err := elem.Backend.GetTextDrawingSize(elem.Handle, widget.Text, false)
 	if err != nil {
 		return
 	}
-	size = constraints.Clamp(goui.Size{Width: intrinsicWidth + padding.Width, Height: intrinsicHeight + padding.Height})
-	l.layoutSize = size
-	return
+	return goui.Size{Width: intrinsicWidth + padding.Width, Height: intrinsicHeight + padding.Height}, nil
+}
+
+func (l *labelLayouter) MinIntrinsicWidth(height int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Width, err
+}
+
+func (l *labelLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Width, err
+}
+
+func (l *labelLayouter) MinIntrinsicHeight(width int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Height, err
+}
+
+func (l *labelLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	size, err := l.intrinsicSize()
+	return size.Height, err
+}
+
+// Baseline returns the ascent of the text, which is drawn at the top of the label.
+func (l *labelLayouter) Baseline() (baseline int, ok bool, err error) {
+	elem := l.Element().(*labelElement)
+	if baseline, _, err = elem.Backend.GetTextMetrics(elem.Handle); err != nil {
+		return
+	}
+	return baseline, true, nil
 }
 
 func (l *labelLayouter) PositionAt(x, y int) (err error) {
-	return native.SetWidgetDimensions(l.Element().(*labelElement).Handle, x, y, l.layoutSize.Width, l.layoutSize.Height)
+	elem := l.Element().(*labelElement)
+	return elem.Backend.SetWidgetDimensions(elem.Handle, x, y, l.layoutSize.Width, l.layoutSize.Height)
 }
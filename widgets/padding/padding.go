@@ -32,6 +32,8 @@ func (p *Padding) Child(n int) goui.Widget {
 
 func (p *Padding) Exclusive(goui.Container) { /*Nop*/ }
 
+func (p *Padding) SingleChild() { /*Nop*/ }
+
 type paddingLayouter struct {
 	goui.LayouterBase
 }
@@ -71,3 +73,58 @@ func (l *paddingLayouter) PositionAt(x, y int) (err error) {
 	}
 	return
 }
+
+func (l *paddingLayouter) Baseline() (baseline int, ok bool, err error) {
+	if baseline, ok, err = l.LayouterBase.Baseline(); !ok {
+		return
+	}
+	return baseline + l.Element().Widget().(*Padding).Top, true, nil
+}
+
+func (l *paddingLayouter) MinIntrinsicWidth(height int) (int, error) {
+	padding := l.Element().Widget().(*Padding)
+	return l.intrinsic(padding.Left+padding.Right, func(child goui.Layouter) (int, error) {
+		return child.MinIntrinsicWidth(deflate(height, padding.Top+padding.Bottom))
+	})
+}
+
+func (l *paddingLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	padding := l.Element().Widget().(*Padding)
+	return l.intrinsic(padding.Left+padding.Right, func(child goui.Layouter) (int, error) {
+		return child.MaxIntrinsicWidth(deflate(height, padding.Top+padding.Bottom))
+	})
+}
+
+func (l *paddingLayouter) MinIntrinsicHeight(width int) (int, error) {
+	padding := l.Element().Widget().(*Padding)
+	return l.intrinsic(padding.Top+padding.Bottom, func(child goui.Layouter) (int, error) {
+		return child.MinIntrinsicHeight(deflate(width, padding.Left+padding.Right))
+	})
+}
+
+func (l *paddingLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	padding := l.Element().Widget().(*Padding)
+	return l.intrinsic(padding.Top+padding.Bottom, func(child goui.Layouter) (int, error) {
+		return child.MaxIntrinsicHeight(deflate(width, padding.Left+padding.Right))
+	})
+}
+
+// intrinsic returns the intrinsic dimension of the child returned by f plus padding.
+func (l *paddingLayouter) intrinsic(padding int, f func(child goui.Layouter) (int, error)) (int, error) {
+	for child := range l.Children() {
+		value, err := f(child)
+		if err != nil {
+			return 0, err
+		}
+		return value + padding, nil // only one child
+	}
+	return padding, nil
+}
+
+// deflate returns extent, which can be [goui.Infinity], minus padding.
+func deflate(extent, padding int) int {
+	if extent == goui.Infinity {
+		return extent
+	}
+	return max(0, extent-padding)
+}
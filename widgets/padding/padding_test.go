@@ -0,0 +1,38 @@
+package padding
+
+import (
+	"testing"
+
+	"github.com/mkch/goui/native/software"
+	"github.com/mkch/goui/widgets/intrinsicheight"
+	"github.com/mkch/goui/widgets/intrinsicwidth"
+	"github.com/mkch/goui/widgets/label"
+	"github.com/mkch/goui/widgets/widgetstest"
+)
+
+func TestPadding_Golden(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &Padding{
+		Left: 10, Top: 20, Right: 30, Bottom: 40,
+		Widget: &label.Label{Text: "Padded"},
+	}, 200, 100)
+	if rect := wt.Rect(wt.Find(widgetstest.ByText("Padded"))); rect.Left != 10 || rect.Top != 20 {
+		t.Fatalf("unexpected label bounds %v", rect)
+	}
+	wt.ExpectGolden("padding", nil)
+}
+
+func TestPadding_Intrinsic(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &intrinsicheight.IntrinsicHeight{
+		Widget: &intrinsicwidth.IntrinsicWidth{
+			Widget: &Padding{
+				Left: 10, Top: 20, Right: 30, Bottom: 40,
+				Widget: &label.Label{Text: "Padded"},
+			},
+		},
+	}, 200, 100)
+	width, height := software.MeasureText("Padded", false)
+	wantWidth, wantHeight := width+10+30, height+20+40
+	if rect := wt.Rect(wt.Find(widgetstest.ByType[*Padding]())); rect.Width() != wantWidth || rect.Height() != wantHeight {
+		t.Fatalf("unexpected padding bounds %v", rect)
+	}
+}
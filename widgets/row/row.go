@@ -9,13 +9,18 @@ import (
 // Row is a [Container] [Widget] that arranges its children in a horizontal row.
 // The height of Row is the maximum height of its children.
 // The width of Row is calculated based on its MainAxisSize property:
-// - If MainAxisSize is Min, the width of Row is the sum of widths of its children.
+// - If MainAxisSize is Min, the width of Row is the sum of widths of its children and the spacing between them.
 // - If MainAxisSize is Max, the width of Row is the maximum width allowed by its parent.
+//
+// The free space in the main axis, if any, is placed according to MainAxisAlignment.
 type Row struct {
 	ID                 goui.ID
 	Widgets            []goui.Widget
 	MainAxisSize       axes.Size
+	MainAxisAlignment  axes.MainAxisAlignment
 	CrossAxisAlignment axes.Alignment
+	Spacing            int  // The gap between adjacent children.
+	Reverse            bool // Whether the children are placed from right to left.
 }
 
 func (row *Row) WidgetID() goui.ID {
@@ -23,18 +28,23 @@ func (row *Row) WidgetID() goui.ID {
 }
 
 func (row *Row) CreateElement(ctx *goui.Context) (goui.Element, error) {
-	return &goui.ElementBase{
-		ElementLayouter: &rowcol.Layouter{
-			Main:               func(s *goui.Size) *int { return &s.Width },
-			Cross:              func(s *goui.Size) *int { return &s.Height },
-			MaxMain:            func(c *goui.Constraints) *int { return &c.MaxWidth },
-			MinMain:            func(c *goui.Constraints) *int { return &c.MinWidth },
-			MaxCross:           func(c *goui.Constraints) *int { return &c.MaxHeight },
-			MinCross:           func(c *goui.Constraints) *int { return &c.MinHeight },
-			MainAxisSize:       func() axes.Size { return row.MainAxisSize },
-			CrossAxisAlignment: func() axes.Alignment { return row.CrossAxisAlignment },
-		},
-	}, nil
+	layouter := &rowcol.Layouter{
+		Horizontal: true,
+		Main:       func(s *goui.Size) *int { return &s.Width },
+		Cross:      func(s *goui.Size) *int { return &s.Height },
+		MaxMain:    func(c *goui.Constraints) *int { return &c.MaxWidth },
+		MinMain:    func(c *goui.Constraints) *int { return &c.MinWidth },
+		MaxCross:   func(c *goui.Constraints) *int { return &c.MaxHeight },
+		MinCross:   func(c *goui.Constraints) *int { return &c.MinHeight },
+	}
+	// The properties are read from the current widget of the element, which is replaced on rebuild.
+	widget := func() *Row { return layouter.Element().Widget().(*Row) }
+	layouter.MainAxisSize = func() axes.Size { return widget().MainAxisSize }
+	layouter.MainAxisAlignment = func() axes.MainAxisAlignment { return widget().MainAxisAlignment }
+	layouter.CrossAxisAlignment = func() axes.Alignment { return widget().CrossAxisAlignment }
+	layouter.Spacing = func() int { return widget().Spacing }
+	layouter.Reverse = func() bool { return widget().Reverse }
+	return &goui.ElementBase{ElementLayouter: layouter}, nil
 }
 
 func (row *Row) NumChildren() int {
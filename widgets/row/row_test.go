@@ -4,10 +4,22 @@ import (
 	"testing"
 
 	"github.com/mkch/goui"
+	"github.com/mkch/goui/native"
+	"github.com/mkch/goui/native/headless"
+	"github.com/mkch/goui/native/software"
 	"github.com/mkch/goui/widgets/axes"
+	"github.com/mkch/goui/widgets/button"
+	"github.com/mkch/goui/widgets/expanded"
+	"github.com/mkch/goui/widgets/label"
+	"github.com/mkch/goui/widgets/textfield"
 	"github.com/mkch/goui/widgets/widgetstest"
 )
 
+// nativeElement is implemented by [goui.NativeElement].
+type nativeElement interface {
+	NativeHandle(*goui.Context) native.Handle
+}
+
 type mockWidget struct {
 	ID      goui.ID
 	Element mockElement
@@ -29,9 +41,12 @@ type mockLayouter struct {
 	goui.LayouterBase
 	IntrinsicSize goui.Size
 	Position      goui.Point
+	TextBaseline  int              // The baseline if not 0.
+	Constraints   goui.Constraints // The constraints of the last layout.
 }
 
 func (l *mockLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
+	l.Constraints = constraints
 	return constraints.Clamp(l.IntrinsicSize), nil
 }
 
@@ -40,6 +55,26 @@ func (l *mockLayouter) PositionAt(x, y int) error {
 	return nil
 }
 
+func (l *mockLayouter) Baseline() (int, bool, error) {
+	return l.TextBaseline, l.TextBaseline != 0, nil
+}
+
+func (l *mockLayouter) MinIntrinsicWidth(height int) (int, error) {
+	return l.IntrinsicSize.Width, nil
+}
+
+func (l *mockLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	return l.IntrinsicSize.Width, nil
+}
+
+func (l *mockLayouter) MinIntrinsicHeight(width int) (int, error) {
+	return l.IntrinsicSize.Height, nil
+}
+
+func (l *mockLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	return l.IntrinsicSize.Height, nil
+}
+
 func Test_RowSize(t *testing.T) {
 	ctx := widgetstest.NewContext()
 	widget1 := &mockWidget{
@@ -204,3 +239,395 @@ func Test_RowAlign(t *testing.T) {
 		t.Fatalf("Unexpected widget2 Y position: got %d, want 20", y)
 	}
 }
+
+func Test_RowFragment(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, width int) *mockWidget {
+		return &mockWidget{
+			ID: goui.ValueID(id),
+			Element: mockElement{
+				ElementBase: goui.ElementBase{
+					ElementLayouter: &mockLayouter{
+						IntrinsicSize: goui.Size{Width: width, Height: 50},
+					},
+				},
+			},
+		}
+	}
+	widget1 := newWidget("widget1", 100)
+	widget2 := newWidget("widget2", 200)
+	widget3 := newWidget("widget3", 50)
+
+	row := &Row{
+		Widgets: []goui.Widget{
+			widget1,
+			&goui.Fragment{Widgets: []goui.Widget{widget2, widget3}},
+		},
+		MainAxisSize: axes.Min,
+	}
+	_, layouter, err := widgetstest.BuildElementTree(ctx, row, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	size, err := layouter.Layout(ctx, goui.Constraints{
+		MaxWidth: 400, MaxHeight: 200,
+	})
+	if err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	if size.Width != 350 || size.Height != 50 {
+		t.Fatalf("Unexpected size: got %v, want Width=350 Height=50", size)
+	}
+	if err = layouter.PositionAt(0, 0); err != nil {
+		t.Fatalf("PositionAt error: %v", err)
+	}
+	for _, want := range []struct {
+		widget *mockWidget
+		x      int
+	}{{widget1, 0}, {widget2, 100}, {widget3, 300}} {
+		if x := want.widget.Element.ElementLayouter.(*mockLayouter).Position.X; x != want.x {
+			t.Fatalf("Unexpected %v X position: got %d, want %d", want.widget.ID, x, want.x)
+		}
+	}
+}
+
+func Test_RowGolden(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &Row{
+		CrossAxisAlignment: axes.Center,
+		Widgets: []goui.Widget{
+			&label.Label{Text: "Name:"},
+			&textfield.TextField{InitialValue: "Alice"},
+			&button.Button{Label: "OK"},
+		},
+	}, 320, 60)
+	wt.ExpectGolden("row", nil)
+}
+
+func Test_RowIntrinsic(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, size goui.Size) goui.Widget {
+		return &mockWidget{
+			ID:      goui.ValueID(id),
+			Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: size}}},
+		}
+	}
+	_, layouter, err := widgetstest.BuildElementTree(ctx, &Row{
+		Widgets: []goui.Widget{
+			newWidget("widget1", goui.Size{Width: 100, Height: 50}),
+			newWidget("widget2", goui.Size{Width: 200, Height: 30}),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	for _, test := range []struct {
+		name      string
+		intrinsic func(int) (int, error)
+		want      int
+	}{
+		{"MinIntrinsicWidth", layouter.MinIntrinsicWidth, 300},
+		{"MaxIntrinsicWidth", layouter.MaxIntrinsicWidth, 300},
+		{"MinIntrinsicHeight", layouter.MinIntrinsicHeight, 50},
+		{"MaxIntrinsicHeight", layouter.MaxIntrinsicHeight, 50},
+	} {
+		got, err := test.intrinsic(goui.Infinity)
+		if err != nil {
+			t.Fatalf("%v error: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Fatalf("Unexpected %v: got %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func Test_RowBaseline(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, size goui.Size, baseline int) *mockWidget {
+		return &mockWidget{
+			ID: goui.ValueID(id),
+			Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{
+				IntrinsicSize: size,
+				TextBaseline:  baseline,
+			}}},
+		}
+	}
+	widget1 := newWidget("widget1", goui.Size{Width: 100, Height: 20}, 15)
+	widget2 := newWidget("widget2", goui.Size{Width: 100, Height: 30}, 20)
+	widget3 := newWidget("widget3", goui.Size{Width: 100, Height: 10}, 0) // No baseline.
+	_, layouter, err := widgetstest.BuildElementTree(ctx, &Row{
+		Widgets:            []goui.Widget{widget1, widget2, widget3},
+		MainAxisSize:       axes.Min,
+		CrossAxisAlignment: axes.Baseline,
+	}, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 400, MaxHeight: 200})
+	if err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	// 20 above the baseline from widget2, and 10 below from widget2.
+	if size.Width != 300 || size.Height != 30 {
+		t.Fatalf("Unexpected size: got %v, want Width=300 Height=30", size)
+	}
+	if err = layouter.PositionAt(0, 0); err != nil {
+		t.Fatalf("PositionAt error: %v", err)
+	}
+	for _, want := range []struct {
+		widget *mockWidget
+		y      int
+	}{{widget1, 5}, {widget2, 0}, {widget3, 0}} {
+		if y := want.widget.Element.ElementLayouter.(*mockLayouter).Position.Y; y != want.y {
+			t.Fatalf("Unexpected %v Y position: got %d, want %d", want.widget.ID, y, want.y)
+		}
+	}
+	if baseline, ok, err := layouter.Baseline(); err != nil || !ok || baseline != 20 {
+		t.Fatalf("Unexpected row baseline: got %d, %v, %v, want 20", baseline, ok, err)
+	}
+
+	// Grows to fit the aligned children.
+	// Other constraints, so the cached size is not used.
+	widget1.Element.ElementLayouter.(*mockLayouter).TextBaseline = 5
+	if size, err = layouter.Layout(ctx, goui.Constraints{MaxWidth: 400, MaxHeight: 100}); err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	// 20 above the baseline from widget2, and 15 below from widget1.
+	if size.Height != 35 {
+		t.Fatalf("Unexpected height: got %d, want 35", size.Height)
+	}
+}
+
+func Test_RowBaselineText(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &Row{
+		CrossAxisAlignment: axes.Baseline,
+		Widgets: []goui.Widget{
+			&label.Label{Text: "Name:"},
+			&textfield.TextField{InitialValue: "Alice"},
+			&button.Button{Label: "OK"},
+		},
+	}, 320, 60)
+	// textTop returns the top of the text of elem relative to the window, as reported by the backend.
+	textTop := func(elem goui.Element) int {
+		t.Helper()
+		bounds := wt.Rect(elem)
+		top, err := wt.Backend().GetTextTop(elem.(nativeElement).NativeHandle(nil), bounds.Height())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bounds.Top + top
+	}
+	expectAligned := func() {
+		t.Helper()
+		label := textTop(wt.Find(widgetstest.ByText("Name:")))
+		field := textTop(wt.Find(widgetstest.ByType[*textfield.TextField]()))
+		button := textTop(wt.Find(widgetstest.ByType[*button.Button]()))
+		if label != field || label != button {
+			t.Fatalf("baselines are not aligned: text tops of label %v, text field %v, button %v", label, field, button)
+		}
+	}
+	// The text is vertically centered in the text field by the software backend.
+	expectAligned()
+	fieldRect := wt.Rect(wt.Find(widgetstest.ByType[*textfield.TextField]()))
+	if label := wt.Rect(wt.Find(widgetstest.ByText("Name:"))); label.Top != fieldRect.Top+(fieldRect.Height()-software.LineHeight)/2 {
+		t.Fatalf("label is not aligned with the centered text: label %v, text field %v", label, fieldRect)
+	}
+
+	// The text is drawn at the top of the text field, inside the border,
+	// as single line EDIT controls of Windows do.
+	const fieldTextTop = 3
+	wt.Backend().TextTop = func(control *headless.Object, height int) int {
+		if control.Kind == headless.TextField {
+			return fieldTextTop
+		}
+		return 0
+	}
+	wt.Resize(320, 80)
+	expectAligned()
+	fieldRect = wt.Rect(wt.Find(widgetstest.ByType[*textfield.TextField]()))
+	if label := wt.Rect(wt.Find(widgetstest.ByText("Name:"))); label.Top != fieldRect.Top+fieldTextTop {
+		t.Fatalf("label is not aligned with the text at the top: label %v, text field %v", label, fieldRect)
+	}
+}
+
+func Test_RowMainAxisAlignment(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	widget1 := &mockWidget{
+		ID:      goui.ValueID("widget1"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 100, Height: 50}}}},
+	}
+	widget2 := &mockWidget{
+		ID:      goui.ValueID("widget2"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 50, Height: 30}}}},
+	}
+	for _, test := range []struct {
+		alignment axes.MainAxisAlignment
+		spacing   int
+		x1, x2    int
+	}{
+		{axes.MainAxisStart, 0, 0, 100},
+		{axes.MainAxisEnd, 0, 150, 250},
+		{axes.MainAxisCenter, 0, 75, 175},
+		{axes.SpaceBetween, 0, 0, 250},
+		{axes.SpaceAround, 0, 37, 212},
+		{axes.SpaceEvenly, 0, 50, 200},
+		{axes.MainAxisStart, 10, 0, 110},
+		{axes.MainAxisEnd, 10, 140, 250},
+		{axes.MainAxisCenter, 10, 70, 180},
+		{axes.SpaceBetween, 10, 0, 250},
+		{axes.SpaceAround, 10, 35, 215},
+	} {
+		_, layouter, err := widgetstest.BuildElementTree(ctx, &Row{
+			Widgets:           []goui.Widget{widget1, widget2},
+			MainAxisAlignment: test.alignment,
+			Spacing:           test.spacing,
+		}, nil)
+		if err != nil {
+			t.Fatalf("BuildElementTree error: %v", err)
+		}
+		size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 300, MaxHeight: 200})
+		if err != nil {
+			t.Fatalf("Layout error: %v", err)
+		}
+		if size.Width != 300 || size.Height != 50 {
+			t.Fatalf("Unexpected size: got %v, want Width=300 Height=50", size)
+		}
+		if err = layouter.PositionAt(0, 0); err != nil {
+			t.Fatalf("PositionAt error: %v", err)
+		}
+		if x := widget1.Element.ElementLayouter.(*mockLayouter).Position.X; x != test.x1 {
+			t.Fatalf("Unexpected widget1 X position with alignment %v and spacing %v: got %d, want %d", test.alignment, test.spacing, x, test.x1)
+		}
+		if x := widget2.Element.ElementLayouter.(*mockLayouter).Position.X; x != test.x2 {
+			t.Fatalf("Unexpected widget2 X position with alignment %v and spacing %v: got %d, want %d", test.alignment, test.spacing, x, test.x2)
+		}
+	}
+}
+
+func Test_RowSpacingReverse(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	newWidget := func(id string, size goui.Size) *mockWidget {
+		return &mockWidget{
+			ID:      goui.ValueID(id),
+			Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: size}}},
+		}
+	}
+	widgets := []*mockWidget{
+		newWidget("widget1", goui.Size{Width: 100, Height: 50}),
+		newWidget("widget2", goui.Size{Width: 50, Height: 30}),
+		newWidget("widget3", goui.Size{Width: 30, Height: 20}),
+	}
+	for _, test := range []struct {
+		size      axes.Size
+		alignment axes.MainAxisAlignment
+		reverse   bool
+		width     int
+		x         []int
+	}{
+		{axes.Min, axes.MainAxisStart, false, 200, []int{0, 110, 170}},
+		{axes.Min, axes.MainAxisStart, true, 200, []int{100, 40, 0}},
+		{axes.Max, axes.MainAxisStart, true, 300, []int{200, 140, 100}},
+		{axes.Max, axes.MainAxisEnd, true, 300, []int{100, 40, 0}},
+	} {
+		_, layouter, err := widgetstest.BuildElementTree(ctx, &Row{
+			Widgets:           []goui.Widget{widgets[0], widgets[1], widgets[2]},
+			MainAxisSize:      test.size,
+			MainAxisAlignment: test.alignment,
+			Spacing:           10,
+			Reverse:           test.reverse,
+		}, nil)
+		if err != nil {
+			t.Fatalf("BuildElementTree error: %v", err)
+		}
+		if width, err := layouter.MaxIntrinsicWidth(goui.Infinity); err != nil {
+			t.Fatalf("MaxIntrinsicWidth error: %v", err)
+		} else if width != 200 {
+			t.Fatalf("Unexpected MaxIntrinsicWidth: got %d, want 200", width)
+		}
+		size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 300, MaxHeight: 200})
+		if err != nil {
+			t.Fatalf("Layout error: %v", err)
+		}
+		if size.Width != test.width || size.Height != 50 {
+			t.Fatalf("Unexpected size: got %v, want Width=%d Height=50", size, test.width)
+		}
+		if err = layouter.PositionAt(0, 0); err != nil {
+			t.Fatalf("PositionAt error: %v", err)
+		}
+		for i, widget := range widgets {
+			if x := widget.Element.ElementLayouter.(*mockLayouter).Position.X; x != test.x[i] {
+				t.Fatalf("Unexpected widget%d X position: got %d, want %d", i+1, x, test.x[i])
+			}
+		}
+	}
+}
+
+func Test_RowStretch(t *testing.T) {
+	ctx := widgetstest.NewContext()
+	widget1 := &mockWidget{
+		ID:      goui.ValueID("widget1"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 100, Height: 50}}}},
+	}
+	widget2 := &mockWidget{
+		ID:      goui.ValueID("widget2"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 50, Height: 30}}}},
+	}
+	_, layouter, err := widgetstest.BuildElementTree(ctx, &Row{
+		Widgets:            []goui.Widget{widget1, &expanded.Expanded{Widget: widget2, Flex: 1}},
+		CrossAxisAlignment: axes.Stretch,
+	}, nil)
+	if err != nil {
+		t.Fatalf("BuildElementTree error: %v", err)
+	}
+	size, err := layouter.Layout(ctx, goui.Constraints{MaxWidth: 300, MaxHeight: 200})
+	if err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	if size.Width != 300 || size.Height != 200 {
+		t.Fatalf("Unexpected size: got %v, want Width=300 Height=200", size)
+	}
+	for i, widget := range []*mockWidget{widget1, widget2} {
+		if c := widget.Element.ElementLayouter.(*mockLayouter).Constraints; c.MinHeight != 200 || c.MaxHeight != 200 {
+			t.Fatalf("Unexpected widget%d constraints: got %v, want MinHeight=200 MaxHeight=200", i+1, c)
+		}
+	}
+
+	// Not stretched in an unbounded cross axis.
+	size, err = layouter.Layout(ctx, goui.Constraints{MaxWidth: 300, MaxHeight: goui.Infinity})
+	if err != nil {
+		t.Fatalf("Layout error: %v", err)
+	}
+	if size.Width != 300 || size.Height != 50 {
+		t.Fatalf("Unexpected size: got %v, want Width=300 Height=50", size)
+	}
+}
+
+func Test_RowRebuild(t *testing.T) {
+	widget1 := &mockWidget{
+		ID:      goui.ValueID("widget1"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 100, Height: 50}}}},
+	}
+	widget2 := &mockWidget{
+		ID:      goui.ValueID("widget2"),
+		Element: mockElement{ElementBase: goui.ElementBase{ElementLayouter: &mockLayouter{IntrinsicSize: goui.Size{Width: 50, Height: 30}}}},
+	}
+	spacing := 0
+	var update goui.UpdateStateFunc
+	root := goui.NewStatefulWidget(nil, func(ctx *goui.Context, updateState goui.UpdateStateFunc) *goui.WidgetState {
+		update = updateState
+		return &goui.WidgetState{Build: func() goui.Widget {
+			return &Row{Widgets: []goui.Widget{widget1, widget2}, Spacing: spacing}
+		}}
+	})
+	wt := widgetstest.NewWidgetTester(t, root, 400, 300)
+	if x := widget2.Element.ElementLayouter.(*mockLayouter).Position.X; x != 100 {
+		t.Fatalf("Unexpected widget2 X position: got %d, want 100", x)
+	}
+	// The new Row of the rebuild is laid out.
+	if err := update(func() { spacing = 10 }); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	wt.Pump()
+	if x := widget2.Element.ElementLayouter.(*mockLayouter).Position.X; x != 110 {
+		t.Fatalf("Unexpected widget2 X position: got %d, want 110", x)
+	}
+}
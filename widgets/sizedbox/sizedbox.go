@@ -34,14 +34,13 @@ func (s *SizedBox) Child(n int) goui.Widget {
 
 func (s *SizedBox) Exclusive(goui.Container) { /*Nop*/ }
 
+func (s *SizedBox) SingleChild() { /*Nop*/ }
+
 type sizedBoxLayouter struct {
 	goui.LayouterBase
-	lastConstraints *goui.Constraints
-	pos             goui.Point
 }
 
 func (l *sizedBoxLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
-	l.lastConstraints = &constraints
 	sizedBox := l.Element().Widget().(*SizedBox)
 	size = constraints.Clamp(goui.Size{Width: sizedBox.Width, Height: sizedBox.Height})
 	for child := range l.Children() {
@@ -63,22 +62,28 @@ func (l *sizedBoxLayouter) Layout(ctx *goui.Context, constraints goui.Constraint
 }
 
 func (l *sizedBoxLayouter) PositionAt(x, y int) (err error) {
-	l.pos = goui.Point{X: x, Y: y}
 	for child := range l.Children() {
 		return child.PositionAt(x, y)
 	}
 	return
 }
 
-func (l *sizedBoxLayouter) Replayer() func(ctx *goui.Context) error {
-	if l.lastConstraints == nil {
-		return nil
-	}
-	return func(ctx *goui.Context) error {
-		_, err := l.Layout(ctx, *l.lastConstraints)
-		if err != nil {
-			return err
-		}
-		return l.PositionAt(l.pos.X, l.pos.Y)
-	}
+func (l *sizedBoxLayouter) FixedSize() bool {
+	return true
+}
+
+func (l *sizedBoxLayouter) MinIntrinsicWidth(height int) (int, error) {
+	return l.Element().Widget().(*SizedBox).Width, nil
+}
+
+func (l *sizedBoxLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	return l.Element().Widget().(*SizedBox).Width, nil
+}
+
+func (l *sizedBoxLayouter) MinIntrinsicHeight(width int) (int, error) {
+	return l.Element().Widget().(*SizedBox).Height, nil
+}
+
+func (l *sizedBoxLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	return l.Element().Widget().(*SizedBox).Height, nil
 }
@@ -1,7 +1,5 @@
 package textfield
 
-import "github.com/mkch/goui/native"
-
 // Controller is used to control a TextField widget.
 type Controller struct {
 	element *textFieldElement
@@ -13,10 +11,10 @@ func (ctrl *Controller) setElement(elem *textFieldElement) {
 
 // Text returns the current text in the TextField.
 func (ctrl *Controller) Text() (string, error) {
-	return native.GetTextFieldText(ctrl.element.Handle)
+	return ctrl.element.Backend.GetTextFieldText(ctrl.element.Handle)
 }
 
 // SetText sets the text in the TextField.
 func (ctrl *Controller) SetText(text string) error {
-	return native.SetTextFieldText(ctrl.element.Handle, text)
+	return ctrl.element.Backend.SetTextFieldText(ctrl.element.Handle, text)
 }
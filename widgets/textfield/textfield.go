@@ -17,7 +17,8 @@ func (txt *TextField) WidgetID() goui.ID {
 }
 
 func (txt *TextField) CreateElement(ctx *goui.Context) (goui.Element, error) {
-	handle, err := native.CreateTextField(ctx.NativeWindow(), txt.InitialValue, txt.Obscure)
+	backend := ctx.NativeBackend()
+	handle, err := backend.CreateTextField(ctx.NativeWindow(), txt.InitialValue, txt.Obscure)
 	if err != nil {
 		return nil, err
 	}
@@ -28,12 +29,13 @@ func (txt *TextField) CreateElement(ctx *goui.Context) (goui.Element, error) {
 			ElementBase: goui.ElementBase{
 				ElementLayouter: layouter,
 			},
-			Handle: handle,
+			Backend: backend,
+			Handle:  handle,
 			DestroyFunc: func(h native.Handle) error {
 				if txt.Controller != nil {
 					txt.Controller.setElement(nil)
 				}
-				return native.DestroyWindow(h)
+				return backend.DestroyWindow(h)
 			},
 		},
 	}
@@ -62,18 +64,51 @@ type textFieldLayouter struct {
 	layoutSize goui.Size
 }
 
+// intrinsicSize is the default size of text fields.
+var intrinsicSize = goui.Size{Width: 200, Height: 30}
+
 func (l *textFieldLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
 	if constraints.TightWidth() && constraints.TightHeight() {
 		size = constraints.MinSize()
 		l.layoutSize = size
 		return
 	}
-	intrinsicWidth, intrinsicHeight := 200, 30 // Default size for text field
-	size = constraints.Clamp(goui.Size{Width: intrinsicWidth, Height: intrinsicHeight})
+	size = constraints.Clamp(intrinsicSize)
 	l.layoutSize = size
 	return
 }
 
+func (l *textFieldLayouter) MinIntrinsicWidth(height int) (int, error) {
+	return intrinsicSize.Width, nil
+}
+
+func (l *textFieldLayouter) MaxIntrinsicWidth(height int) (int, error) {
+	return intrinsicSize.Width, nil
+}
+
+func (l *textFieldLayouter) MinIntrinsicHeight(width int) (int, error) {
+	return intrinsicSize.Height, nil
+}
+
+func (l *textFieldLayouter) MaxIntrinsicHeight(width int) (int, error) {
+	return intrinsicSize.Height, nil
+}
+
+// Baseline returns the baseline of the single line text, where the backend draws it in the text field.
+func (l *textFieldLayouter) Baseline() (baseline int, ok bool, err error) {
+	elem := l.Element().(*textFieldElement)
+	ascent, _, err := elem.Backend.GetTextMetrics(elem.Handle)
+	if err != nil {
+		return
+	}
+	top, err := elem.Backend.GetTextTop(elem.Handle, l.layoutSize.Height)
+	if err != nil {
+		return
+	}
+	return top + ascent, true, nil
+}
+
 func (l *textFieldLayouter) PositionAt(x, y int) (err error) {
-	return native.SetWidgetDimensions(l.Element().(*textFieldElement).Handle, x, y, l.layoutSize.Width, l.layoutSize.Height)
+	elem := l.Element().(*textFieldElement)
+	return elem.Backend.SetWidgetDimensions(elem.Handle, x, y, l.layoutSize.Width, l.layoutSize.Height)
 }
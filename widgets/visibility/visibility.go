@@ -3,7 +3,6 @@ package visibility
 import (
 	"github.com/mkch/gg"
 	"github.com/mkch/goui"
-	"github.com/mkch/goui/native"
 )
 
 // Visibility is a [Container] [Widget] that shows or hides its single child
@@ -23,7 +22,7 @@ func (p *Visibility) WidgetID() goui.ID {
 
 func (p *Visibility) CreateElement(ctx *goui.Context) (goui.Element, error) {
 	return &goui.ElementBase{
-		ElementLayouter: &visibilityLayouter{},
+		ElementLayouter: &visibilityLayouter{ctx: ctx},
 	}, nil
 }
 
@@ -37,21 +36,17 @@ func (p *Visibility) Child(n int) goui.Widget {
 
 func (p *Visibility) Exclusive(goui.Container) { /*Nop*/ }
 
+func (p *Visibility) SingleChild() { /*Nop*/ }
+
 type visibilityLayouter struct {
 	goui.LayouterBase
-	// X offset of child.
-	// 0 if visible. Beyond right edge of window if not visible.
-	childXOffset int
+	ctx *goui.Context // Used to query the window size.
 }
 
 func (l *visibilityLayouter) Layout(ctx *goui.Context, constraints goui.Constraints) (size goui.Size, err error) {
 	visibility := l.Element().Widget().(*Visibility)
 	for child := range l.Children() {
 		if !visibility.Visible {
-			// Set the offset beyond the right edge of the window.
-			if _, _, l.childXOffset, _, err = native.WindowClientRect(ctx.NativeWindow()); err != nil {
-				return
-			}
 			if visibility.MaintainSize {
 				// Use the child's size.
 				size, err = child.Layout(ctx, constraints)
@@ -61,17 +56,25 @@ func (l *visibilityLayouter) Layout(ctx *goui.Context, constraints goui.Constrai
 			}
 			return
 		}
-		// Visible
-		l.childXOffset = 0                    // Normal position
 		return child.Layout(ctx, constraints) // Normal layout
 	}
 	return constraints.MinSize(), nil
 }
 
 func (l *visibilityLayouter) PositionAt(x, y int) (err error) {
+	visibility := l.Element().Widget().(*Visibility)
 	for child := range l.Children() {
-		// See Layout() for the offset logic.
-		return child.PositionAt(x+l.childXOffset, y)
+		if !visibility.Visible {
+			// Set the offset beyond the right edge of the window.
+			// The window size is queried here rather than in Layout, because
+			// the cached layout size doesn't change when the window is resized.
+			var width int
+			if _, _, width, _, err = l.ctx.NativeBackend().WindowClientRect(l.ctx.NativeWindow()); err != nil {
+				return
+			}
+			x += width
+		}
+		return child.PositionAt(x, y)
 	}
 	return
 }
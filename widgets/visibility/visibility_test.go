@@ -0,0 +1,27 @@
+package visibility
+
+import (
+	"testing"
+
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/widgets/label"
+	"github.com/mkch/goui/widgets/sizedbox"
+	"github.com/mkch/goui/widgets/widgetstest"
+)
+
+func Test_VisibilityResize(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, &sizedbox.SizedBox{
+		Width:  100,
+		Height: 30,
+		Widget: &Visibility{Widget: &label.Label{ID: goui.ValueID("label"), Text: "hidden"}},
+	}, 400, 300)
+	hidden := wt.Find(widgetstest.ByID(goui.ValueID("label")))
+	if rect := wt.Rect(hidden); rect.Left < 400 {
+		t.Fatalf("hidden label is in the window: %v", rect)
+	}
+	// The Visibility has the same constraints under the SizedBox, and its layout is cached.
+	wt.Resize(800, 300)
+	if rect := wt.Rect(hidden); rect.Left < 800 {
+		t.Fatalf("hidden label is in the window after resizing: %v", rect)
+	}
+}
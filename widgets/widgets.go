@@ -5,6 +5,8 @@ import (
 	"github.com/mkch/goui/widgets/center"
 	"github.com/mkch/goui/widgets/column"
 	"github.com/mkch/goui/widgets/expanded"
+	"github.com/mkch/goui/widgets/intrinsicheight"
+	"github.com/mkch/goui/widgets/intrinsicwidth"
 	"github.com/mkch/goui/widgets/label"
 	"github.com/mkch/goui/widgets/padding"
 	"github.com/mkch/goui/widgets/row"
@@ -33,3 +35,7 @@ type TextFieldController = textfield.Controller
 type Expanded = expanded.Expanded
 
 type Visibility = visibility.Visibility
+
+type IntrinsicWidth = intrinsicwidth.IntrinsicWidth
+
+type IntrinsicHeight = intrinsicheight.IntrinsicHeight
@@ -0,0 +1,162 @@
+package widgetstest
+
+import (
+	"errors"
+	"flag"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGoldens = flag.Bool("update-goldens", false, "regenerate golden files instead of comparing with them")
+
+// GoldenDir is the directory of golden files, relative to the directory of the test package.
+const GoldenDir = "testdata"
+
+// FailureDir is the directory, relative to [GoldenDir], where the actual and diff images are
+// written when a golden comparison fails.
+const FailureDir = "failures"
+
+// GoldenOptions controls how images are compared with golden files.
+type GoldenOptions struct {
+	// Tolerance is the maximum difference of any color channel(0-255)
+	// for two pixels to be considered equal.
+	Tolerance int
+	// MaxDiffPixels is the number of different pixels allowed.
+	MaxDiffPixels int
+}
+
+// Screenshot paints the window and returns the image.
+func (wt *WidgetTester) Screenshot() *image.RGBA {
+	return wt.software.Render(wt.window)
+}
+
+// ExpectGolden compares the screenshot of the window with the golden file name.png.
+// See [ExpectGolden].
+func (wt *WidgetTester) ExpectGolden(name string, opts *GoldenOptions) {
+	wt.tb.Helper()
+	ExpectGolden(wt.tb, wt.Screenshot(), name, opts)
+}
+
+// ExpectGolden compares img with the PNG golden file name.png in [GoldenDir].
+// Opts can be nil, which means the images must be identical.
+//
+// If the images differ, the test fails, and name_actual.png and name_diff.png are written
+// into [FailureDir]. Different pixels are red in the diff image.
+//
+// If the test binary runs with the -update-goldens flag, the golden file is overwritten
+// with img instead.
+func ExpectGolden(tb testing.TB, img image.Image, name string, opts *GoldenOptions) {
+	tb.Helper()
+	if opts == nil {
+		opts = &GoldenOptions{}
+	}
+	goldenPath := filepath.Join(GoldenDir, filepath.FromSlash(name)+".png")
+	if *updateGoldens {
+		if err := writePNG(goldenPath, img); err != nil {
+			tb.Fatalf("update golden: %v", err)
+		}
+		return
+	}
+
+	golden, err := readPNG(goldenPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		tb.Fatalf("golden file %v does not exist, run the test with -update-goldens to create it", goldenPath)
+	}
+	if err != nil {
+		tb.Fatalf("read golden: %v", err)
+	}
+
+	diff, numDiff := diffImages(golden, img, opts.Tolerance)
+	if numDiff <= opts.MaxDiffPixels {
+		return
+	}
+	failurePrefix := filepath.Join(GoldenDir, FailureDir, strings.ReplaceAll(name, "/", "_"))
+	if err = writePNG(failurePrefix+"_actual.png", img); err != nil {
+		tb.Errorf("write actual image: %v", err)
+	}
+	if err = writePNG(failurePrefix+"_diff.png", diff); err != nil {
+		tb.Errorf("write diff image: %v", err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		tb.Fatalf("image bounds %v does not match golden %v bounds %v", img.Bounds(), goldenPath, golden.Bounds())
+	}
+	tb.Fatalf("%d pixels differ from golden %v, see %v_diff.png", numDiff, goldenPath, failurePrefix)
+}
+
+// diffImages compares img with golden pixel by pixel.
+// It returns an image of the union bounds, where equal pixels are the faded golden and
+// different pixels are red, and the number of different pixels.
+// A pixel out of the bounds of either image is different.
+func diffImages(golden, img image.Image, tolerance int) (diff *image.RGBA, numDiff int) {
+	bounds := golden.Bounds().Union(img.Bounds())
+	diff = image.NewRGBA(bounds)
+	red := color.RGBA{0xFF, 0, 0, 0xFF}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			if !p.In(golden.Bounds()) || !p.In(img.Bounds()) {
+				diff.SetRGBA(x, y, red)
+				numDiff++
+				continue
+			}
+			c1 := color.RGBAModel.Convert(golden.At(x, y)).(color.RGBA)
+			c2 := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if !colorEqual(c1, c2, tolerance) {
+				diff.SetRGBA(x, y, red)
+				numDiff++
+				continue
+			}
+			gray := color.GrayModel.Convert(c1).(color.Gray)
+			faded := uint8(0xC0 + int(gray.Y)/4)
+			diff.SetRGBA(x, y, color.RGBA{faded, faded, faded, 0xFF})
+		}
+	}
+	return
+}
+
+// colorEqual returns whether the difference of every channel of c1 and c2 is not greater than tolerance.
+func colorEqual(c1, c2 color.RGBA, tolerance int) bool {
+	within := func(a, b uint8) bool {
+		return max(a, b)-min(a, b) <= uint8(min(max(tolerance, 0), 0xFF))
+	}
+	return within(c1.R, c2.R) && within(c1.G, c2.G) && within(c1.B, c2.B) && within(c1.A, c2.A)
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	// Normalize to RGBA so that the color model of the file does not matter.
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba, nil
+}
+
+func writePNG(path string, img image.Image) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return png.Encode(f, img)
+}
@@ -0,0 +1,28 @@
+package widgetstest
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDiffImages(t *testing.T) {
+	golden := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.SetRGBA(0, 0, color.RGBA{3, 0, 0, 0})
+	img.SetRGBA(1, 1, color.RGBA{0, 0, 10, 0})
+
+	if _, n := diffImages(golden, img, 0); n != 2 {
+		t.Fatalf("expected 2 different pixels, got %d", n)
+	}
+	diff, n := diffImages(golden, img, 5)
+	if n != 1 {
+		t.Fatalf("expected 1 different pixel with tolerance, got %d", n)
+	}
+	if c := diff.RGBAAt(1, 1); c != (color.RGBA{0xFF, 0, 0, 0xFF}) {
+		t.Fatalf("different pixel is not red in diff image: %v", c)
+	}
+	if _, n = diffImages(golden, image.NewRGBA(image.Rect(0, 0, 3, 2)), 0); n != 2 {
+		t.Fatalf("expected 2 different pixels out of bounds, got %d", n)
+	}
+}
@@ -0,0 +1,231 @@
+package widgetstest
+
+import (
+	"slices"
+	"testing"
+	_ "unsafe" // for go:linkname
+
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/native"
+	"github.com/mkch/goui/native/headless"
+	"github.com/mkch/goui/native/software"
+)
+
+//go:linkname newTestApp
+
+// newTestApp creates a new App which records the layout results of all elements.
+func newTestApp(config *goui.AppConfig) *goui.App
+
+//go:linkname buildWindows
+
+// buildWindows builds and lays out all windows of app, as App.Run does before running the message loop.
+func buildWindows(app *goui.App) error
+
+//go:linkname rootElement
+
+// rootElement returns the root element of the window with ID windowID, or nil if not found.
+func rootElement(app *goui.App, windowID goui.ID) goui.Element
+
+//go:linkname elementChildren
+
+// elementChildren returns the child elements of elem.
+func elementChildren(elem goui.Element) []goui.Element
+
+//go:linkname elementBounds
+
+// elementBounds returns the last laid out bounds of elem.
+func elementBounds(elem goui.Element) (bounds goui.Rect, ok bool)
+
+// Matcher reports whether an element matches some condition.
+type Matcher func(elem goui.Element) bool
+
+// ByID returns a Matcher that matches elements whose widget has the given ID.
+func ByID(id goui.ID) Matcher {
+	return func(elem goui.Element) bool {
+		return elem.Widget().WidgetID() == id
+	}
+}
+
+// ByType returns a Matcher that matches elements whose widget is of type W.
+func ByType[W goui.Widget]() Matcher {
+	return func(elem goui.Element) bool {
+		_, ok := elem.Widget().(W)
+		return ok
+	}
+}
+
+// ByText returns a Matcher that matches native elements showing text,
+// such as labels and buttons with the text as label.
+func ByText(text string) Matcher {
+	return func(elem goui.Element) bool {
+		obj := nativeObject(elem)
+		return obj != nil && obj.Kind != headless.TextField && obj.Text == text
+	}
+}
+
+// nativeElement is implemented by [goui.NativeElement].
+type nativeElement interface {
+	NativeHandle(*goui.Context) native.Handle
+}
+
+// nativeObject returns the headless object of a native element, or nil if elem is not native.
+func nativeObject(elem goui.Element) *headless.Object {
+	if native, ok := elem.(nativeElement); ok {
+		if obj, ok := native.NativeHandle(nil).(*headless.Object); ok {
+			return obj
+		}
+	}
+	return nil
+}
+
+// WidgetTester shows a widget in a window of a [software.Backend] and
+// simulates user interactions on it.
+// Create a WidgetTester with [NewWidgetTester].
+type WidgetTester struct {
+	tb       testing.TB
+	app      *goui.App
+	software *software.Backend
+	backend  *headless.Backend
+	windowID goui.ID
+	window   *headless.Object
+}
+
+// NewWidgetTester creates a WidgetTester showing root in a window with the given client size.
+// The element tree is built and laid out before NewWidgetTester returns.
+// Any error fails tb.
+func NewWidgetTester(tb testing.TB, root goui.Widget, width, height int) *WidgetTester {
+	tb.Helper()
+	backend := software.New()
+	wt := &WidgetTester{
+		tb:       tb,
+		app:      newTestApp(&goui.AppConfig{Debug: &goui.Debug{}, Backend: backend}),
+		software: backend,
+		backend:  backend.Backend,
+		windowID: goui.ValueID("widgetstest"),
+	}
+	if err := wt.app.CreateWindow(goui.Window{
+		ID:     wt.windowID,
+		Title:  tb.Name(),
+		Width:  width,
+		Height: height,
+		Root:   root,
+	}); err != nil {
+		tb.Fatalf("CreateWindow error: %v", err)
+	}
+	wt.window = backend.Windows()[0]
+	if err := buildWindows(wt.app); err != nil {
+		tb.Fatalf("build error: %v", err)
+	}
+	wt.Pump()
+	return wt
+}
+
+// App returns the app running the widget.
+func (wt *WidgetTester) App() *goui.App {
+	return wt.app
+}
+
+// Backend returns the native backend of the app.
+func (wt *WidgetTester) Backend() *headless.Backend {
+	return wt.backend
+}
+
+// Window returns the native window showing the widget.
+func (wt *WidgetTester) Window() *headless.Object {
+	return wt.window
+}
+
+// Pump runs the functions posted to the app, such as state updates from other goroutines.
+func (wt *WidgetTester) Pump() {
+	wt.backend.RunPending()
+}
+
+// Root returns the root element.
+func (wt *WidgetTester) Root() goui.Element {
+	return rootElement(wt.app, wt.windowID)
+}
+
+// FindAll returns all elements matching m, in depth first order.
+func (wt *WidgetTester) FindAll(m Matcher) (found []goui.Element) {
+	root := wt.Root()
+	if root == nil {
+		return nil
+	}
+	stack := []goui.Element{root}
+	for len(stack) > 0 {
+		elem := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if m(elem) {
+			found = append(found, elem)
+		}
+		children := elementChildren(elem)
+		slices.Reverse(children)
+		stack = append(stack, children...)
+	}
+	return
+}
+
+// Find returns the only element matching m.
+// It fails the test if the number of matching elements is not exactly one.
+func (wt *WidgetTester) Find(m Matcher) goui.Element {
+	wt.tb.Helper()
+	found := wt.FindAll(m)
+	if len(found) != 1 {
+		wt.tb.Fatalf("expected exactly 1 matching element, found %d", len(found))
+	}
+	return found[0]
+}
+
+// native returns the headless object of elem, and fails the test if elem is not a
+// native element of the given kind.
+func (wt *WidgetTester) native(elem goui.Element, kind headless.Kind) *headless.Object {
+	wt.tb.Helper()
+	obj := nativeObject(elem)
+	if obj == nil || obj.Kind != kind {
+		wt.tb.Fatalf("element of %T is not a native %v", elem.Widget(), kind)
+	}
+	return obj
+}
+
+// Tap clicks a button element and pumps.
+func (wt *WidgetTester) Tap(elem goui.Element) {
+	wt.tb.Helper()
+	wt.native(elem, headless.Button).Click()
+	wt.Pump()
+}
+
+// EnterText replaces the text of a text field element as if typed by the user, and pumps.
+func (wt *WidgetTester) EnterText(elem goui.Element, text string) {
+	wt.tb.Helper()
+	wt.native(elem, headless.TextField).Text = text
+	wt.Pump()
+}
+
+// Resize resizes the client area of the window and pumps.
+func (wt *WidgetTester) Resize(width, height int) {
+	wt.window.Resize(width, height)
+	wt.Pump()
+}
+
+// Rect returns the bounds of elem relative to the window, as last laid out.
+// For an element without a layouter, the bounds of its nearest child with layouter is returned.
+// It fails the test if elem has not been laid out.
+func (wt *WidgetTester) Rect(elem goui.Element) goui.Rect {
+	wt.tb.Helper()
+	bounds, ok := elementBounds(elem)
+	if !ok {
+		wt.tb.Fatalf("element of %T has no layout", elem.Widget())
+	}
+	return bounds
+}
+
+// Text returns the text shown by a native element, such as the text of a label,
+// the label of a button, or the content of a text field.
+func (wt *WidgetTester) Text(elem goui.Element) string {
+	wt.tb.Helper()
+	obj := nativeObject(elem)
+	if obj == nil {
+		wt.tb.Fatalf("element of %T is not native", elem.Widget())
+	}
+	return obj.Text
+}
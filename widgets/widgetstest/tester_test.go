@@ -0,0 +1,72 @@
+package widgetstest_test
+
+import (
+	"testing"
+
+	"github.com/mkch/gg"
+	"github.com/mkch/goui"
+	"github.com/mkch/goui/native/software"
+	"github.com/mkch/goui/widgets"
+	"github.com/mkch/goui/widgets/axes"
+	"github.com/mkch/goui/widgets/widgetstest"
+)
+
+func echoForm() goui.Widget {
+	var ctrl widgets.TextFieldController
+	return goui.StatefulWidgetFunc(func(ctx *goui.Context, updateState goui.UpdateStateFunc) *goui.WidgetState {
+		var echo = "nothing"
+		return &goui.WidgetState{
+			Build: func() goui.Widget {
+				return &widgets.Center{
+					Widget: &widgets.Column{
+						MainAxisSize:       axes.Min,
+						CrossAxisAlignment: axes.Center,
+						Widgets: []goui.Widget{
+							&widgets.TextField{ID: goui.ValueID("input"), Controller: &ctrl},
+							&widgets.Button{
+								Label: "Echo",
+								OnClick: func(*goui.Context) {
+									text := gg.Must(ctrl.Text())
+									gg.MustOK(updateState(func() { echo = text }))
+								},
+							},
+							&widgets.Label{ID: goui.ValueID("echo"), Text: echo},
+						},
+					},
+				}
+			},
+		}
+	})
+}
+
+func TestWidgetTester(t *testing.T) {
+	wt := widgetstest.NewWidgetTester(t, echoForm(), 400, 300)
+
+	textField := wt.Find(widgetstest.ByType[*widgets.TextField]())
+	if rect := wt.Rect(textField); rect.Width() != 200 || rect.Height() != 30 {
+		t.Fatalf("unexpected text field bounds %v", rect)
+	}
+	column := wt.Find(widgetstest.ByType[*widgets.Column]())
+	columnRect := wt.Rect(column)
+	if columnRect.Left != 100 || columnRect.Right != 300 {
+		t.Fatalf("column is not centered horizontally: %v", columnRect)
+	}
+
+	wt.EnterText(textField, "hello")
+	wt.Tap(wt.Find(widgetstest.ByText("Echo")))
+	echo := wt.Find(widgetstest.ByID(goui.ValueID("echo")))
+	if text := wt.Text(echo); text != "hello" {
+		t.Fatalf("unexpected echo %q", text)
+	}
+	if rect := wt.Rect(echo); rect.Width() != 5*software.CellWidth {
+		t.Fatalf("unexpected echo width %v", rect.Width())
+	}
+	if wt.Find(widgetstest.ByText("hello")) != echo {
+		t.Fatalf("ByText does not find the label")
+	}
+
+	wt.Resize(600, 300)
+	if rect := wt.Rect(column); rect.Left != 200 || rect.Top != columnRect.Top {
+		t.Fatalf("column is not centered after resizing: %v", rect)
+	}
+}
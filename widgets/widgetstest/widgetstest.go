@@ -5,6 +5,7 @@ import (
 	_ "unsafe" // for go:linkname
 
 	"github.com/mkch/goui"
+	"github.com/mkch/goui/native/headless"
 )
 
 //go:linkname BuildElementTree
@@ -17,8 +18,13 @@ import (
 // the returned Layouter is the layouter of the Element or its nearest child.
 func BuildElementTree(ctx *goui.Context, widget goui.Widget, parentLayouter goui.Layouter) (goui.Element, goui.Layouter, error)
 
-//go:linkname NewContext
+//go:linkname newContext
+
+// newContext creates and returns a new mock goui.Context with the given config.
+func newContext(config *goui.AppConfig) *goui.Context
 
 // NewContext creates and returns a new mock goui.Context for testing.
-// No OS specific resources are allocated.
-func NewContext() *goui.Context
+// The context uses a [headless.Backend], so no OS specific resources are allocated.
+func NewContext() *goui.Context {
+	return newContext(&goui.AppConfig{Debug: &goui.Debug{}, Backend: headless.New()})
+}
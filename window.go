@@ -1,6 +1,10 @@
 package goui
 
-import "github.com/mkch/goui/native"
+import (
+	"context"
+
+	"github.com/mkch/goui/native"
+)
 
 type Window struct {
 	ID      ID
@@ -17,4 +21,34 @@ type window struct {
 	Handle   native.Handle
 	Root     Element  // Root element.
 	Layouter Layouter // Layouter for the root element.
+	// ctx is the context of the window, created on demand, see [window.context].
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed bool
+}
+
+// context returns the context of the window, which is cancelled when the window is closed.
+// The contexts of the elements in the window are derived from it.
+func (w *window) context() context.Context {
+	if w.ctx == nil {
+		w.ctx, w.cancel = context.WithCancel(context.Background())
+		if w.closed {
+			w.cancel()
+		}
+	}
+	return w.ctx
+}
+
+// closeWindow removes the element tree of the closed window, cancels the context of the window,
+// and forgets the window.
+func (app *App) closeWindow(w *window) {
+	if w.Root != nil {
+		app.removeElementTree(w.Root)
+		w.Root, w.Layouter = nil, nil
+	}
+	w.closed = true
+	if w.cancel != nil {
+		w.cancel()
+	}
+	delete(app.windows, w.ID)
 }
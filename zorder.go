@@ -0,0 +1,84 @@
+package goui
+
+import "github.com/mkch/goui/native"
+
+// nativeHandleElement is an element owning a native control, such as [NativeElement].
+type nativeHandleElement interface {
+	Element
+	NativeHandle(*Context) native.Handle
+}
+
+// The native controls of a window are kept in the z-order and tab order of
+// the depth-first order of their elements. Controls are created in that order
+// when an element tree is built, but the controls created or moved by
+// reconciliation must be placed with [placeNativeTree].
+
+// nativeHandle returns the native handle of elem itself, or nil if none.
+func nativeHandle(ctx *Context, elem Element) native.Handle {
+	if e, ok := elem.(nativeHandleElement); ok {
+		return e.NativeHandle(ctx)
+	}
+	return nil
+}
+
+// appendNativeHandles appends the native handles in the element tree rooted at elem
+// to handles in depth-first order, and returns the extended slice.
+func appendNativeHandles(ctx *Context, handles []native.Handle, elem Element) []native.Handle {
+	if handle := nativeHandle(ctx, elem); handle != nil {
+		handles = append(handles, handle)
+	}
+	for i := range elem.numChildren() {
+		handles = appendNativeHandles(ctx, handles, elem.child(i))
+	}
+	return handles
+}
+
+// lastNativeHandle returns the last native handle in depth-first order
+// in the element tree rooted at elem, or nil if none.
+func lastNativeHandle(ctx *Context, elem Element) native.Handle {
+	for i := elem.numChildren() - 1; i >= 0; i-- {
+		if handle := lastNativeHandle(ctx, elem.child(i)); handle != nil {
+			return handle
+		}
+	}
+	return nativeHandle(ctx, elem)
+}
+
+// precedingNativeHandle returns the last native handle before the nth child
+// of parent in depth-first order, or nil if none.
+func precedingNativeHandle(ctx *Context, parent Element, n int) native.Handle {
+	for parent != nil {
+		for i := n - 1; i >= 0; i-- {
+			if handle := lastNativeHandle(ctx, parent.child(i)); handle != nil {
+				return handle
+			}
+		}
+		if handle := nativeHandle(ctx, parent); handle != nil {
+			return handle
+		}
+		grandparent := parent.parent()
+		if grandparent == nil {
+			break
+		}
+		n = grandparent.indexChild(parent)
+		parent = grandparent
+	}
+	return nil
+}
+
+// placeNativeTree moves the native controls in the element tree of the nth child
+// of parent to their places in the z-order and tab order of the window.
+func placeNativeTree(ctx *Context, parent Element, n int) error {
+	handles := appendNativeHandles(ctx, nil, parent.child(n))
+	if len(handles) == 0 {
+		return nil
+	}
+	after := precedingNativeHandle(ctx, parent, n)
+	for _, handle := range handles {
+		if err := ctx.app.backend.SetWidgetZOrder(handle, after); err != nil {
+			return err
+		}
+		after = handle
+	}
+	return nil
+}
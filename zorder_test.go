@@ -0,0 +1,79 @@
+package goui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mkch/goui/native/headless"
+)
+
+// nativeWidget is a widget of a headless label with text.
+type nativeWidget struct {
+	ID   ID
+	text string
+}
+
+func (w *nativeWidget) WidgetID() ID {
+	return w.ID
+}
+
+func (w *nativeWidget) CreateElement(ctx *Context) (Element, error) {
+	handle, err := ctx.NativeBackend().CreateLabel(ctx.NativeWindow(), w.text)
+	if err != nil {
+		return nil, err
+	}
+	return &NativeElement{
+		ElementBase: ElementBase{ElementLayouter: &mockLayouter{}},
+		Backend:     ctx.NativeBackend(),
+		Handle:      handle,
+		DestroyFunc: ctx.NativeBackend().DestroyWindow,
+	}, nil
+}
+
+func TestUpdateContainerElement_NativeOrder(t *testing.T) {
+	backend := headless.New()
+	ctx := newMockContext(&AppConfig{Debug: &Debug{}, Backend: backend})
+	var err error
+	if ctx.window.Handle, err = backend.CreateWindow("", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	labels := func(texts ...string) (widgets []Widget) {
+		for _, text := range texts {
+			widgets = append(widgets, &nativeWidget{ID: ValueID(text), text: text})
+		}
+		return
+	}
+	nested := func(texts ...string) Widget {
+		return &layouterContainer{layouter: &countingLayouter{}, children: labels(texts...)}
+	}
+	order := func() (texts []string) {
+		for _, obj := range ctx.window.Handle.(*headless.Object).Children {
+			texts = append(texts, obj.Text)
+		}
+		return
+	}
+
+	root, _, err := buildElementTree(ctx, &layouterContainer{layouter: &countingLayouter{}, children: append(labels("a", "b", "c"), nested("d", "e"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Moves and creations in the outer container, and a creation in the nested one.
+	_, _, err = reconcileElementTree(ctx, root, &layouterContainer{layouter: &countingLayouter{}, children: append(labels("c", "x", "a", "b"), nested("d", "y", "e"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"c", "x", "a", "b", "d", "y", "e"}; !slices.Equal(order(), want) {
+		t.Fatalf("expected native order %q, got %q", want, order())
+	}
+	// Only the moved and created controls are reordered,
+	// the nested container matched bottom-up is reconciled first.
+	var moved []string
+	for _, op := range backend.Ops() {
+		if op.Name == "SetWidgetZOrder" {
+			moved = append(moved, op.Object.Text)
+		}
+	}
+	if want := []string{"y", "c", "x"}; !slices.Equal(moved, want) {
+		t.Fatalf("expected %q to be reordered, got %q", want, moved)
+	}
+}